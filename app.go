@@ -1,31 +1,65 @@
 package simpleapi
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"go/token"
+	"io"
 	"net/http"
 	"reflect"
+	"runtime"
+	"strings"
+	"sync"
 
 	"github.com/sattvikc/go-simpleapi/handler"
+	"github.com/sattvikc/go-simpleapi/openapi"
 	"github.com/sattvikc/go-simpleapi/router"
-	"github.com/sattvikc/go-simpleapi/swagger"
+	"github.com/sattvikc/go-simpleapi/websocket"
 )
 
 type App struct {
-	r           *router.Router
-	swaggerJson map[string]interface{}
+	mu              sync.RWMutex
+	r               *router.Router
+	doc             openapi.Document
+	endpoints       []*Endpoint
+	routes          map[*handler.Handler]*Endpoint
+	spec            *specCache
+	specHooks       []func(doc *openapi.Document)
+	operationIDs    map[string]bool
+	securitySchemes map[string]*SecurityScheme
+	docs            docsConfig
+	providers       map[reflect.Type]*handler.Provider
+	overrides       map[reflect.Type]*handler.Provider
+	// endpointOverrides maps the handlers of registered endpoints to those
+	// replacing them.
+	endpointOverrides map[*handler.Handler]*Endpoint
+	validate          func(err *ResponseValidationError)
+	mock              MockMode
+	upgrader          websocket.Upgrader
 }
 
-func New() *App {
+func New(opts ...Option) *App {
 	s := &App{
 		r: router.New(),
-		swaggerJson: map[string]interface{}{
-			"openapi": "3.0.0",
-			"info": map[string]interface{}{
-				"title":   "FastAPI",
-				"version": "1.0.0",
+		doc: openapi.Document{
+			OpenAPI: "3.0.0",
+			Info: Info{
+				Title:   "simpleapi",
+				Version: "1.0.0",
 			},
-			"paths": map[string]interface{}{},
 		},
+		routes:          map[*handler.Handler]*Endpoint{},
+		operationIDs:    map[string]bool{},
+		securitySchemes: map[string]*SecurityScheme{},
+		docs:            defaultDocsConfig(),
+		providers:       map[reflect.Type]*handler.Provider{},
+		overrides:       map[reflect.Type]*handler.Provider{},
+
+		endpointOverrides: map[*handler.Handler]*Endpoint{},
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
 	addSwaggerRoutes(s)
 	return s
@@ -36,25 +70,72 @@ func (s *App) ListenAndServe(addr string) error {
 }
 
 func (s *App) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
 	h, params := s.r.FindCall(r.URL.Path, r.Method)
+	var endpoint *Endpoint
+	var mocked bool
+	var handlers *handler.Handler
+	if h != nil {
+		handlers = h.(*handler.Handler)
+		endpoint = s.routes[handlers]
+		mocked = endpoint != nil && endpoint.mocked
+		if override, ok := s.endpointOverrides[handlers]; ok {
+			handlers = override.handlerInstances
+			mocked = override.mocked
+		}
+	}
+	validate := s.validate
+	mock := s.mock
+	s.mu.RUnlock()
 
 	if h == nil {
 		// TODO handler not found
 		return
 	}
 
+	// Mocks validate the request body against the document, so the body of
+	// a mocked endpoint is kept for after the handlers have read it.
+	var body []byte
+	if mocked && r.Body != nil {
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		body = data
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	response := newResponseWriter(w)
+	if validate != nil && endpoint != nil {
+		response.capture = &bytes.Buffer{}
+	}
+
 	ctx := &Context{
+		app:      s,
 		Request:  r,
-		Response: w,
+		Response: response,
 		params:   params,
-		next:     h.(*handler.Handler).Clone(),
+		next:     handlers.Clone(),
 	}
 
 	err := ctx.Next()
+	if ctx.ws != nil {
+		ctx.ws.Close()
+	}
+	// Routes added with AddHandler declare nothing to mock.
+	if err != nil && mock != 0 && endpoint != nil && errors.Is(err, ErrNotImplemented) && !response.Written() {
+		err = s.writeMock(endpoint, ctx, body, mocked)
+	}
 	if err != nil {
-		fmt.Println(err)
-		// TODO handle error
+		writeError(ctx, err)
+	}
+	if response.capture != nil {
+		if verr := s.validateResponse(endpoint, ctx, response.capture.Bytes()); verr != nil {
+			validate(verr)
+		}
 	}
+	ctx.runAfter(err)
 }
 
 func (s *App) AddHandler(path string, method string, handlers ...interface{}) error {
@@ -62,71 +143,218 @@ func (s *App) AddHandler(path string, method string, handlers ...interface{}) er
 	if err != nil {
 		return err
 	}
+	s.mu.Lock()
 	s.r.Add(path, method, h, "")
+	s.mu.Unlock()
 
 	return nil
 }
 
-func (s *App) addToSwagger(path string, handlers *handler.Handler, method string, tags []string, responseTypes []struct {
-	code        int
-	response    interface{}
-	description string
-}) {
-	definition := map[string]interface{}{
-		"parameters": []interface{}{},
-		"responses":  map[string]interface{}{},
-		"tags":       tags,
+// Provide registers a dependency provider. A provider is a function like
+//
+//	func(ctx *Context, h AuthHeader) (*User, error)
+//
+// whose first result can then be declared as a parameter of any handler or
+// other provider. Its parameters are bound from the request like a handler's
+// and are included in the OpenAPI operations of the endpoints that use it.
+// The provider runs at most once per request. Providers must be registered
+// before the endpoints that use them; Endpoint panics when a parameter has no
+// provider.
+func (s *App) Provide(provider interface{}) {
+	p, err := handler.NewProvider(provider)
+	if err != nil {
+		panic(err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.providers[p.Out] = p
+	s.spec = nil
+}
+
+// Override replaces the provider for the type returned by provider until
+// ResetOverrides is called, or until the returned function restores the
+// override it replaced, if any. It is meant for swapping dependencies in
+// tests.
+func (s *App) Override(provider interface{}) (restore func()) {
+	p, err := handler.NewProvider(provider)
+	if err != nil {
+		panic(err)
 	}
 
-	if method != "get" {
-		definition["requestBody"] = map[string]interface{}{}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	previous, ok := s.overrides[p.Out]
+	s.overrides[p.Out] = p
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.overrides[p.Out] != p {
+			return
+		}
+		if ok {
+			s.overrides[p.Out] = previous
+		} else {
+			delete(s.overrides, p.Out)
+		}
 	}
+}
+
+// ResetOverrides removes all providers registered with Override and all
+// endpoints replaced with OverrideEndpoint.
+func (s *App) ResetOverrides() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.overrides = map[reflect.Type]*handler.Provider{}
+	s.endpointOverrides = map[*handler.Handler]*Endpoint{}
+}
+
+func (s *App) provider(t reflect.Type) *handler.Provider {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if p, ok := s.overrides[t]; ok {
+		return p
+	}
+	return s.providers[t]
+}
+
+// requestParamTypes expands the parameters of a handler into the request
+// bound structs of the handler and of the providers it depends on.
+func (s *App) requestParamTypes(paramTypes []reflect.Type, seen map[reflect.Type]bool) []reflect.Type {
+	result := []reflect.Type{}
+	for _, paramType := range paramTypes {
+		if paramType.Kind() == reflect.Struct {
+			result = append(result, paramType)
+			continue
+		}
 
-	for _, handler := range *handlers {
-		swagger.UpdateDefinitionUsingParamTypes(definition, handler.ParamTypes)
+		p := s.providers[paramType]
+		if p == nil || seen[paramType] {
+			continue
+		}
+		seen[paramType] = true
+		result = append(result, s.requestParamTypes(p.ParamTypes, seen)...)
 	}
+	return result
+}
 
-	if _, ok := s.swaggerJson["paths"].(map[string]interface{})[path]; !ok {
-		s.swaggerJson["paths"].(map[string]interface{})[path] = map[string]interface{}{}
+// unresolvable returns a parameter type of the handlers that is neither
+// bound from the request nor supplied by a provider or the context, or nil.
+func (s *App) unresolvable(paramTypes []reflect.Type, seen map[reflect.Type]bool) reflect.Type {
+	for _, paramType := range paramTypes {
+		if paramType.Kind() == reflect.Struct || paramType == wsConnType || seen[paramType] {
+			continue
+		}
+
+		p := s.providers[paramType]
+		if p == nil {
+			return paramType
+		}
+		seen[paramType] = true
+		if t := s.unresolvable(p.ParamTypes, seen); t != nil {
+			return t
+		}
 	}
+	return nil
+}
 
-	if _, ok := s.swaggerJson["paths"].(map[string]interface{})[path].(map[string]interface{})[method]; !ok {
-		s.swaggerJson["paths"].(map[string]interface{})[path].(map[string]interface{})[method] = definition
+// checkParams panics when a handler parameter has no provider, so that it
+// is not found on the first request.
+func (s *App) checkParams(e *Endpoint) {
+	seen := map[reflect.Type]bool{}
+	for _, h := range *e.handlerInstances {
+		if t := s.unresolvable(h.ParamTypes, seen); t != nil {
+			panic(fmt.Sprintf("no provider registered for %v, a parameter of %s %s", t, strings.ToUpper(e.method), e.path))
+		}
 	}
+}
 
-	responses := definition["responses"].(map[string]interface{})
-	for _, responseType := range responseTypes {
-		codeStr := fmt.Sprintf("%d", responseType.code)
-		schema := swagger.GetSwaggerSchemaForType(reflect.TypeOf(responseType.response))
+// operationID reserves the operationId of an endpoint. Explicit ids must be
+// unique, derived ones get a numeric suffix when taken.
+func (s *App) operationID(e *Endpoint) string {
+	if e.operationID != "" {
+		if s.operationIDs[e.operationID] {
+			panic(fmt.Sprintf("duplicate operation id %q", e.operationID))
+		}
+		s.operationIDs[e.operationID] = true
+		return e.operationID
+	}
 
-		if _, ok := responses[codeStr]; !ok {
-			responses[codeStr] = map[string]interface{}{
-				"description": responseType.description,
-				"content": map[string]interface{}{
-					"application/json": map[string]interface{}{
-						"schema": schema,
-					},
-				},
+	id := e.builderName
+	if id == "" {
+		id = e.method
+		for _, part := range strings.Split(e.path, "/") {
+			part = strings.Trim(part, "{}*")
+			if part != "" {
+				id += strings.ToUpper(part[:1]) + part[1:]
 			}
-		} else {
-			responses[codeStr].(map[string]interface{})["description"] = responses[codeStr].(map[string]interface{})["description"].(string) + " or " + responseType.description
-
-			eSchema := responses[codeStr].(map[string]interface{})["content"].(map[string]interface{})["application/json"].(map[string]interface{})["schema"].(map[string]interface{})
-			if _, ok := eSchema["oneOf"]; !ok {
-				responses[codeStr].(map[string]interface{})["content"].(map[string]interface{})["application/json"].(map[string]interface{})["schema"] = map[string]interface{}{
-					"oneOf": []interface{}{
-						eSchema,
-						schema,
-					},
+		}
+	}
+
+	candidate := id
+	for i := 2; s.operationIDs[candidate]; i++ {
+		candidate = fmt.Sprintf("%s_%d", id, i)
+	}
+	s.operationIDs[candidate] = true
+	return candidate
+}
+
+// funcName returns the name of a named function, or "" for closures.
+func funcName(fn interface{}) string {
+	f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer())
+	if f == nil {
+		return ""
+	}
+
+	name := f.Name()
+	name = name[strings.LastIndex(name, "/")+1:]
+	name = name[strings.Index(name, ".")+1:]
+	if strings.Contains(name, ".") || !token.IsIdentifier(name) {
+		return ""
+	}
+	return name
+}
+
+func (s *App) Endpoint(path string, handlerFuncs ...func(e *Endpoint) interface{}) {
+	s.newEndpoint(path, handlerFuncs).register()
+}
+
+// OverrideEndpoint replaces the handlers of a registered endpoint until
+// ResetOverrides is called, or until the returned function restores the
+// override it replaced, if any. The builders run like those passed to
+// Endpoint and must declare the same method; what they document is ignored.
+// It is meant for swapping a handler in tests.
+func (s *App) OverrideEndpoint(path string, handlerFuncs ...func(e *Endpoint) interface{}) (restore func()) {
+	override := s.newEndpoint(path, handlerFuncs)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkParams(override)
+	for _, e := range s.endpoints {
+		if e.path == path && e.method == override.method {
+			key := e.handlerInstances
+			previous, ok := s.endpointOverrides[key]
+			s.endpointOverrides[key] = override
+			return func() {
+				s.mu.Lock()
+				defer s.mu.Unlock()
+				if s.endpointOverrides[key] != override {
+					return
+				}
+				if ok {
+					s.endpointOverrides[key] = previous
+				} else {
+					delete(s.endpointOverrides, key)
 				}
-			} else {
-				eSchema["oneOf"] = append(eSchema["oneOf"].([]interface{}), schema)
 			}
 		}
 	}
+	panic(fmt.Sprintf("no %s endpoint registered for %s", strings.ToUpper(override.method), path))
 }
 
-func (s *App) Endpoint(path string, handlerFuncs ...func(e *Endpoint) interface{}) {
+// newEndpoint runs the builders of an endpoint and creates its handlers.
+func (s *App) newEndpoint(path string, handlerFuncs []func(e *Endpoint) interface{}) *Endpoint {
 	e := &Endpoint{
 		app:      s,
 		path:     path,
@@ -137,6 +365,19 @@ func (s *App) Endpoint(path string, handlerFuncs ...func(e *Endpoint) interface{
 	for i, handlerFunc := range handlerFuncs {
 		e.handlers[i] = handlerFunc(e)
 	}
+	s.mockHandler(e)
+	if len(handlerFuncs) > 0 {
+		e.builderName = funcName(handlerFuncs[len(handlerFuncs)-1])
+	}
+
+	if len(e.after) > 0 {
+		e.handlers = append([]interface{}{func(ctx *Context) error {
+			for _, fn := range e.after {
+				ctx.After(fn)
+			}
+			return ctx.Next()
+		}}, e.handlers...)
+	}
 
 	handlerInstances, err := handler.New(e.handlers...)
 	if err != nil {
@@ -144,5 +385,5 @@ func (s *App) Endpoint(path string, handlerFuncs ...func(e *Endpoint) interface{
 	}
 
 	e.handlerInstances = handlerInstances
-	e.register()
+	return e
 }
@@ -0,0 +1,740 @@
+package simpleapi_test
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/sattvikc/go-simpleapi"
+	"github.com/sattvikc/go-simpleapi/openapi"
+	"github.com/sattvikc/go-simpleapi/ui"
+	"github.com/sattvikc/go-simpleapi/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+type progress struct {
+	Percent int `json:"percent"`
+}
+
+func TestServerSentEvents(t *testing.T) {
+	app := simpleapi.New()
+	app.Endpoint("/progress", func(e *simpleapi.Endpoint) interface{} {
+		e.WithEventStream(200, progress{}, "Progress updates").GET()
+
+		return func(ctx *simpleapi.Context) error {
+			sse, err := ctx.SSE(200)
+			if err != nil {
+				return err
+			}
+			defer sse.Close()
+
+			sse.Retry(3 * time.Second)
+			sse.Comment("hello")
+			sse.Send("progress", "1", progress{Percent: 50})
+			return sse.Send("", "", "multi\nline")
+		}
+	})
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/progress", nil))
+
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	assert.Equal(t, "retry: 3000\n\n"+
+		": hello\n\n"+
+		"id: 1\nevent: progress\ndata: {\"percent\":50}\n\n"+
+		"data: multi\ndata: line\n\n", w.Body.String())
+
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+
+	spec := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &spec))
+	content := spec["paths"].(map[string]interface{})["/progress"].(map[string]interface{})["get"].(map[string]interface{})["responses"].(map[string]interface{})["200"].(map[string]interface{})["content"].(map[string]interface{})
+	assert.Contains(t, content, "text/event-stream")
+}
+
+func TestWebSocketEndpoint(t *testing.T) {
+	type chat struct {
+		Room  string `path:"room"`
+		Token string `header:"X-Token"`
+	}
+	type message struct {
+		Text string `json:"text"`
+	}
+
+	app := simpleapi.New()
+	app.Endpoint("/chat/{room}", func(e *simpleapi.Endpoint) interface{} {
+		e.WebSocket()
+
+		return func(ctx *simpleapi.Context, req chat, conn *websocket.Conn) error {
+			var msg message
+			if err := conn.ReadJSON(&msg); err != nil {
+				return err
+			}
+			return conn.WriteJSON(message{Text: req.Room + ":" + req.Token + ":" + msg.Text})
+		}
+	})
+
+	server := httptest.NewServer(app)
+	defer server.Close()
+
+	conn, _, err := websocket.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/chat/general", http.Header{
+		"X-Token": []string{"secret"},
+	})
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	assert.NoError(t, conn.WriteJSON(message{Text: "hi"}))
+	var got message
+	assert.NoError(t, conn.ReadJSON(&got))
+	assert.Equal(t, "general:secret:hi", got.Text)
+}
+
+func TestWebSocketOrigins(t *testing.T) {
+	echo := func(e *simpleapi.Endpoint) interface{} {
+		e.WebSocket()
+
+		return func(ctx *simpleapi.Context, conn *websocket.Conn) error {
+			return conn.WriteJSON("hi")
+		}
+	}
+	origin := http.Header{"Origin": []string{"https://app.example"}}
+
+	app := simpleapi.New()
+	app.Endpoint("/echo", echo)
+	server := httptest.NewServer(app)
+	defer server.Close()
+
+	_, resp, err := websocket.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/echo", origin)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	app = simpleapi.New(simpleapi.WithWebSocketOrigins("https://app.example"))
+	app.Endpoint("/echo", echo)
+	allowed := httptest.NewServer(app)
+	defer allowed.Close()
+
+	conn, _, err := websocket.Dial("ws"+strings.TrimPrefix(allowed.URL, "http")+"/echo", origin)
+	assert.NoError(t, err)
+	defer conn.Close()
+	var got string
+	assert.NoError(t, conn.ReadJSON(&got))
+	assert.Equal(t, "hi", got)
+}
+
+func TestResponseWriterRecordsStatusAndSize(t *testing.T) {
+	var status, size int
+
+	app := simpleapi.New()
+	app.Endpoint("/books", func(e *simpleapi.Endpoint) interface{} {
+		return func(ctx *simpleapi.Context) error {
+			err := ctx.Next()
+			status, size = ctx.Response.Status(), ctx.Response.Size()
+			return err
+		}
+	}, func(e *simpleapi.Endpoint) interface{} {
+		e.GET()
+
+		return func(ctx *simpleapi.Context) error {
+			err := ctx.JSON(201, map[string]string{"status": "OK"})
+			ctx.Response.WriteHeader(500)
+			return err
+		}
+	})
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/books", nil))
+
+	assert.Equal(t, 201, w.Code)
+	assert.Equal(t, "15", w.Header().Get("Content-Length"))
+	assert.Equal(t, 201, status)
+	assert.Equal(t, 15, size)
+}
+
+func TestRequestScopedValues(t *testing.T) {
+	type user struct {
+		Name string
+	}
+
+	app := simpleapi.New()
+	app.Endpoint("/me", func(e *simpleapi.Endpoint) interface{} {
+		return func(ctx *simpleapi.Context) error {
+			ctx.Set("user", &user{Name: "sattvik"})
+
+			deadlineCtx, cancel := context.WithTimeout(ctx.Request.Context(), time.Minute)
+			defer cancel()
+			ctx.SetContext(deadlineCtx)
+
+			return ctx.Next()
+		}
+	}, func(e *simpleapi.Endpoint) interface{} {
+		e.GET()
+
+		return func(ctx *simpleapi.Context) error {
+			u, ok := simpleapi.Value[*user](ctx, "user")
+			assert.True(t, ok)
+
+			_, ok = simpleapi.Value[string](ctx, "user")
+			assert.False(t, ok)
+
+			_, hasDeadline := ctx.Deadline()
+			assert.True(t, hasDeadline)
+			assert.Equal(t, u, ctx.Value("user"))
+
+			return ctx.JSON(200, map[string]string{"name": u.Name})
+		}
+	})
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/me", nil))
+	assert.Equal(t, `{"name":"sattvik"}`, w.Body.String())
+}
+
+func TestAbortAndAfterHooks(t *testing.T) {
+	var calls []string
+
+	withAuth := func(e *simpleapi.Endpoint) interface{} {
+		e.After(func(ctx *simpleapi.Context, err error) {
+			calls = append(calls, "endpoint after")
+		})
+
+		return func(ctx *simpleapi.Context, headers struct {
+			Authorization *string `header:"Authorization"`
+		}) error {
+			ctx.After(func(ctx *simpleapi.Context, err error) {
+				calls = append(calls, "auth after")
+				assert.True(t, ctx.IsAborted())
+				assert.Error(t, err)
+			})
+
+			if headers.Authorization == nil {
+				return ctx.AbortWithError(401, errors.New("missing token"))
+			}
+			return ctx.Next()
+		}
+	}
+
+	app := simpleapi.New()
+	app.Endpoint("/books", withAuth, func(e *simpleapi.Endpoint) interface{} {
+		e.GET()
+
+		return func(ctx *simpleapi.Context) error {
+			calls = append(calls, "handler")
+			return ctx.JSON(200, map[string]string{"status": "OK"})
+		}
+	})
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/books", nil))
+
+	assert.Equal(t, 401, w.Code)
+	assert.Equal(t, `{"error":"missing token"}`, w.Body.String())
+	assert.Equal(t, []string{"auth after", "endpoint after"}, calls)
+}
+
+func TestUnhandledErrorRespondsWithInternalServerError(t *testing.T) {
+	app := simpleapi.New()
+	app.Endpoint("/fail", func(e *simpleapi.Endpoint) interface{} {
+		e.GET()
+
+		return func(ctx *simpleapi.Context) error {
+			return errors.New("pq: password authentication failed for user admin")
+		}
+	})
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/fail", nil))
+	assert.Equal(t, 500, w.Code)
+	assert.Equal(t, `{"error":"Internal Server Error"}`, w.Body.String())
+}
+
+func TestBindErrorRespondsWithUnprocessableEntity(t *testing.T) {
+	app := simpleapi.New()
+	app.Endpoint("/books", func(e *simpleapi.Endpoint) interface{} {
+		e.GET()
+
+		return func(ctx *simpleapi.Context, req struct {
+			Limit int `query:"limit"`
+		}) error {
+			return ctx.JSON(200, map[string]int{"limit": req.Limit})
+		}
+	})
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/books?limit=ten", nil))
+	assert.Equal(t, 422, w.Code)
+	assert.Contains(t, w.Body.String(), `invalid syntax`)
+}
+
+type user struct {
+	Name string
+}
+
+type authHeader struct {
+	User string `header:"X-User"`
+}
+
+func TestDependencyInjection(t *testing.T) {
+	calls := 0
+
+	app := simpleapi.New()
+	app.Provide(func(ctx *simpleapi.Context, h authHeader) (*user, error) {
+		calls++
+		if h.User == "" {
+			return nil, simpleapi.NewHTTPError(401, errors.New("unauthorised"))
+		}
+		return &user{Name: h.User}, nil
+	})
+	app.Endpoint("/me", func(e *simpleapi.Endpoint) interface{} {
+		return func(ctx *simpleapi.Context, u *user) error {
+			return ctx.Next()
+		}
+	}, func(e *simpleapi.Endpoint) interface{} {
+		e.GET()
+
+		return func(ctx *simpleapi.Context, u *user) error {
+			return ctx.JSON(200, map[string]string{"name": u.Name})
+		}
+	})
+
+	{
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		req.Header.Set("X-User", "sattvik")
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, req)
+		assert.Equal(t, `{"name":"sattvik"}`, w.Body.String())
+		assert.Equal(t, 1, calls)
+	}
+
+	{
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/me", nil))
+		assert.Equal(t, 401, w.Code)
+	}
+
+	{
+		app.Override(func(ctx *simpleapi.Context) (*user, error) {
+			return &user{Name: "test"}, nil
+		})
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/me", nil))
+		assert.Equal(t, `{"name":"test"}`, w.Body.String())
+
+		app.ResetOverrides()
+		w = httptest.NewRecorder()
+		app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/me", nil))
+		assert.Equal(t, 401, w.Code)
+	}
+
+	{
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+		spec := map[string]interface{}{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &spec))
+		parameters := spec["paths"].(map[string]interface{})["/me"].(map[string]interface{})["get"].(map[string]interface{})["parameters"].([]interface{})
+		assert.Len(t, parameters, 1)
+		assert.Equal(t, "X-User", parameters[0].(map[string]interface{})["name"])
+	}
+}
+
+func TestEndpointWithoutProviderPanics(t *testing.T) {
+	app := simpleapi.New()
+	assert.PanicsWithValue(t, "no provider registered for *simpleapi_test.user, a parameter of GET /me", func() {
+		app.Endpoint("/me", func(e *simpleapi.Endpoint) interface{} {
+			e.GET()
+
+			return func(ctx *simpleapi.Context, u *user) error {
+				return nil
+			}
+		})
+	})
+
+	type session struct{}
+	app.Provide(func(ctx *simpleapi.Context, s *session) (*user, error) {
+		return &user{}, nil
+	})
+	assert.PanicsWithValue(t, "no provider registered for *simpleapi_test.session, a parameter of GET /me", func() {
+		app.Endpoint("/me", func(e *simpleapi.Endpoint) interface{} {
+			e.GET()
+
+			return func(ctx *simpleapi.Context, u *user) error {
+				return nil
+			}
+		})
+	})
+}
+
+func TestDocumentMetadataOptions(t *testing.T) {
+	app := simpleapi.New(
+		simpleapi.WithInfo(simpleapi.Info{
+			Title:       "Books",
+			Description: "Book store",
+			License:     &simpleapi.License{Name: "MIT"},
+		}),
+		simpleapi.WithServers(simpleapi.Server{URL: "https://api.example.com"}),
+		simpleapi.WithTags(simpleapi.Tag{Name: "Books", Description: "Manage books"}),
+	)
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+
+	spec := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &spec))
+	assert.Equal(t, map[string]interface{}{
+		"title":       "Books",
+		"description": "Book store",
+		"license":     map[string]interface{}{"name": "MIT"},
+		"version":     "1.0.0",
+	}, spec["info"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"url": "https://api.example.com"}}, spec["servers"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"name": "Books", "description": "Manage books"}}, spec["tags"])
+}
+
+func listBooks(e *simpleapi.Endpoint) interface{} {
+	e.WithSummary("List books").
+		WithDescription("Returns every book in the store.").
+		WithExternalDocs("https://example.com/books", "Book docs").
+		Deprecated().
+		GET()
+
+	return func(ctx *simpleapi.Context) error {
+		return ctx.JSON(200, []string{})
+	}
+}
+
+func TestOperationMetadata(t *testing.T) {
+	app := simpleapi.New()
+	app.Endpoint("/books", listBooks)
+	app.Endpoint("/v2/books", listBooks)
+	app.Endpoint("/books/{id}", func(e *simpleapi.Endpoint) interface{} {
+		e.GET()
+		return func(ctx *simpleapi.Context) error { return nil }
+	})
+	app.Endpoint("/books/{id}", func(e *simpleapi.Endpoint) interface{} {
+		e.WithOperationID("deleteBook").DELETE()
+		return func(ctx *simpleapi.Context) error { return nil }
+	})
+
+	assert.Panics(t, func() {
+		app.Endpoint("/books/{id}/copy", func(e *simpleapi.Endpoint) interface{} {
+			e.WithOperationID("deleteBook").POST()
+			return func(ctx *simpleapi.Context) error { return nil }
+		})
+	})
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+
+	spec := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &spec))
+	paths := spec["paths"].(map[string]interface{})
+
+	list := paths["/books"].(map[string]interface{})["get"].(map[string]interface{})
+	assert.Equal(t, "listBooks", list["operationId"])
+	assert.Equal(t, "List books", list["summary"])
+	assert.Equal(t, "Returns every book in the store.", list["description"])
+	assert.Equal(t, true, list["deprecated"])
+	assert.Equal(t, map[string]interface{}{"url": "https://example.com/books", "description": "Book docs"}, list["externalDocs"])
+
+	assert.Equal(t, "listBooks_2", paths["/v2/books"].(map[string]interface{})["get"].(map[string]interface{})["operationId"])
+	assert.Equal(t, "getBooksId", paths["/books/{id}"].(map[string]interface{})["get"].(map[string]interface{})["operationId"])
+	assert.Equal(t, "deleteBook", paths["/books/{id}"].(map[string]interface{})["delete"].(map[string]interface{})["operationId"])
+}
+
+func TestSecuritySchemes(t *testing.T) {
+	app := simpleapi.New(
+		simpleapi.WithSecurityScheme("bearer", simpleapi.HTTPBearer("JWT")),
+		simpleapi.WithSecurityScheme("oauth", simpleapi.OAuth2(simpleapi.OAuthFlows{
+			AuthorizationCode: &simpleapi.OAuthFlow{
+				AuthorizationURL: "https://auth.example.com/authorize",
+				TokenURL:         "https://auth.example.com/token",
+				Scopes:           map[string]string{"books:write": "Modify books"},
+			},
+		})),
+		simpleapi.WithSecurity("bearer"),
+	)
+	app.Endpoint("/books", func(e *simpleapi.Endpoint) interface{} {
+		e.WithSecurity("oauth", "books:write").POST()
+		return func(ctx *simpleapi.Context) error { return nil }
+	})
+	app.Endpoint("/health", func(e *simpleapi.Endpoint) interface{} {
+		e.WithoutSecurity().GET()
+		return func(ctx *simpleapi.Context) error { return nil }
+	})
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+
+	spec := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &spec))
+
+	schemes := spec["components"].(map[string]interface{})["securitySchemes"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"type": "http", "scheme": "bearer", "bearerFormat": "JWT"}, schemes["bearer"])
+	assert.Equal(t, "oauth2", schemes["oauth"].(map[string]interface{})["type"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"bearer": []interface{}{}}}, spec["security"])
+
+	paths := spec["paths"].(map[string]interface{})
+	assert.Equal(t, []interface{}{map[string]interface{}{"oauth": []interface{}{"books:write"}}}, paths["/books"].(map[string]interface{})["post"].(map[string]interface{})["security"])
+	assert.Equal(t, []interface{}{}, paths["/health"].(map[string]interface{})["get"].(map[string]interface{})["security"])
+
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs/oauth2-redirect", nil))
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), "swaggerUIRedirectOauth2")
+}
+
+func TestSelfHostedDocs(t *testing.T) {
+	assets := fstest.MapFS{
+		"swagger-ui-bundle.js": &fstest.MapFile{Data: []byte("// bundle")},
+		"swagger-ui.css":       &fstest.MapFile{Data: []byte("/* css */")},
+		"redoc.standalone.js":  &fstest.MapFile{Data: []byte("// redoc")},
+		"secret.txt":           &fstest.MapFile{Data: []byte("secret")},
+	}
+
+	app := simpleapi.New(
+		simpleapi.WithOpenAPIPath("/api/spec.json"),
+		simpleapi.WithSwaggerUI("/api/docs"),
+		simpleapi.WithSwaggerUIParameters(map[string]interface{}{"docExpansion": "none"}),
+		simpleapi.WithReDoc("/api/redoc"),
+		simpleapi.WithDocsAssets(assets),
+	)
+
+	{
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/docs", nil))
+		assert.Equal(t, 200, w.Code)
+		assert.Contains(t, w.Body.String(), `<script src="/api/docs/assets/swagger-ui-bundle.js">`)
+		assert.Contains(t, w.Body.String(), `"docExpansion":"none"`)
+		assert.Contains(t, w.Body.String(), `url: "/api/spec.json"`)
+		assert.Contains(t, w.Body.String(), `"/api/docs/oauth2-redirect"`)
+		// Assets that are not available are left out rather than loaded
+		// from a CDN.
+		assert.NotContains(t, w.Body.String(), "https://")
+		assert.NotContains(t, w.Body.String(), "shortcut icon")
+	}
+
+	{
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/docs/assets/swagger-ui-bundle.js", nil))
+		assert.Equal(t, 200, w.Code)
+		assert.Equal(t, "// bundle", w.Body.String())
+
+		w = httptest.NewRecorder()
+		app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/docs/assets/missing.js", nil))
+		assert.Equal(t, 404, w.Code)
+
+		w = httptest.NewRecorder()
+		app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/docs/assets/secret.txt", nil))
+		assert.Equal(t, 404, w.Code)
+	}
+
+	{
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/redoc", nil))
+		assert.Contains(t, w.Body.String(), `<redoc spec-url="/api/spec.json">`)
+		assert.Contains(t, w.Body.String(), `<script src="/api/docs/assets/redoc.standalone.js">`)
+	}
+
+	{
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/spec.json", nil))
+		assert.Equal(t, 200, w.Code)
+	}
+}
+
+func TestEmbeddedDocs(t *testing.T) {
+	app := simpleapi.New()
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), `href="/docs/assets/swagger-ui.css"`)
+	assert.Contains(t, w.Body.String(), `href="/docs/assets/favicon-32x32.png"`)
+	assert.Contains(t, w.Body.String(), `<script src="/docs/assets/swagger-ui-bundle.js">`)
+	assert.NotContains(t, w.Body.String(), "https://")
+
+	for _, name := range []string{"swagger-ui-bundle.js", "swagger-ui.css", "favicon-32x32.png"} {
+		w = httptest.NewRecorder()
+		app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs/assets/"+name, nil))
+		assert.Equal(t, 200, w.Code, name)
+		assert.NotEmpty(t, w.Body.Bytes(), name)
+	}
+
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs/assets/README.md", nil))
+	assert.Equal(t, 404, w.Code)
+}
+
+func TestEmbeddedReDoc(t *testing.T) {
+	if !ui.Has(ui.Assets, ui.ReDocBundle.Name) || !ui.Has(ui.Assets, ui.SwaggerUITheme.Name) {
+		t.Skip("the ReDoc bundle and the Swagger UI theme are not in ui/dist; run go generate in ui")
+	}
+	app := simpleapi.New(simpleapi.WithReDoc("/redoc"))
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/redoc", nil))
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), `<script src="/docs/assets/redoc.standalone.js">`)
+	assert.NotContains(t, w.Body.String(), "https://")
+
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	assert.Contains(t, w.Body.String(), `href="/docs/assets/theme-muted.min.css"`)
+
+	for _, name := range []string{"redoc.standalone.js", "theme-muted.min.css"} {
+		w = httptest.NewRecorder()
+		app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs/assets/"+name, nil))
+		assert.Equal(t, 200, w.Code, name)
+		assert.NotEmpty(t, w.Body.Bytes(), name)
+	}
+}
+
+func TestDocsCDN(t *testing.T) {
+	// Only Swagger UI is available locally.
+	assets := fstest.MapFS{
+		"swagger-ui-bundle.js": {Data: []byte("bundle")},
+		"swagger-ui.css":       {Data: []byte("css")},
+		"favicon-32x32.png":    {Data: []byte("icon")},
+	}
+	assert.PanicsWithValue(t, "documentation asset redoc.standalone.js is missing; provide it with WithDocsAssets or enable WithDocsCDN", func() {
+		simpleapi.New(simpleapi.WithReDoc("/redoc"), simpleapi.WithDocsAssets(assets))
+	})
+
+	app := simpleapi.New(simpleapi.WithReDoc("/redoc"), simpleapi.WithDocsAssets(assets), simpleapi.WithDocsCDN())
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/redoc", nil))
+	assert.Contains(t, w.Body.String(), `<script src="https://cdn.jsdelivr.net/npm/redoc@`)
+	assert.Contains(t, w.Body.String(), `href="/docs/assets/favicon-32x32.png"`)
+
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	assert.Contains(t, w.Body.String(), `<script src="/docs/assets/swagger-ui-bundle.js">`)
+	assert.Contains(t, w.Body.String(), `https://cdn.jsdelivr.net/npm/swagger-ui-themes@`)
+}
+
+func TestDisabledDocs(t *testing.T) {
+	app := simpleapi.New(simpleapi.WithOpenAPIPath(""))
+
+	// The pages are answered like a route that was never registered.
+	unknown := httptest.NewRecorder()
+	app.ServeHTTP(unknown, httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+	for _, path := range []string{"/docs", "/docs/assets/swagger-ui.css", "/openapi.json", "/openapi.yaml"} {
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, unknown.Code, w.Code, path)
+		assert.Equal(t, unknown.Body.String(), w.Body.String(), path)
+	}
+}
+
+func TestSpecIsCached(t *testing.T) {
+	app := simpleapi.New()
+	app.Endpoint("/books", listBooks)
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	etag := w.Header().Get("ETag")
+	assert.Equal(t, 200, w.Code)
+	assert.NotEmpty(t, etag)
+
+	r := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+	r.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.Bytes())
+
+	r = httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+	r.Header.Set("Accept-Encoding", "gzip")
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, r)
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	zr, err := gzip.NewReader(w.Body)
+	assert.NoError(t, err)
+	spec := map[string]interface{}{}
+	assert.NoError(t, json.NewDecoder(zr).Decode(&spec))
+	assert.Contains(t, spec["paths"], "/books")
+
+	app.Endpoint("/authors", func(e *simpleapi.Endpoint) interface{} {
+		e.GET()
+		return func(ctx *simpleapi.Context) error {
+			return nil
+		}
+	})
+
+	r = httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+	r.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, r)
+	assert.Equal(t, 200, w.Code)
+	assert.NotEqual(t, etag, w.Header().Get("ETag"))
+	spec = map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &spec))
+	assert.Contains(t, spec["paths"], "/authors")
+}
+
+func TestConcurrentRegistration(t *testing.T) {
+	app := simpleapi.New()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			app.Endpoint(fmt.Sprintf("/items%d", i), func(e *simpleapi.Endpoint) interface{} {
+				e.GET()
+				return func(ctx *simpleapi.Context) error {
+					return ctx.JSON(200, i)
+				}
+			})
+		}(i)
+		go func() {
+			defer wg.Done()
+			w := httptest.NewRecorder()
+			app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+			assert.Equal(t, 200, w.Code)
+		}()
+	}
+	wg.Wait()
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	spec := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &spec))
+	assert.Len(t, spec["paths"], 20)
+
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items7", nil))
+	assert.Equal(t, "7", w.Body.String())
+}
+
+func TestSpecHooks(t *testing.T) {
+	app := simpleapi.New()
+	app.Endpoint("/books", listBooks)
+	app.OnSpec(func(doc *openapi.Document) {
+		doc.Paths["/books"].Get.Summary = "All books"
+		doc.Info.Title = "Hooked"
+	})
+
+	doc, err := app.Spec()
+	assert.NoError(t, err)
+	assert.Equal(t, "Hooked", doc.Info.Title)
+	assert.Equal(t, "All books", doc.Paths["/books"].Get.Summary)
+	assert.Equal(t, "listBooks", doc.Paths["/books"].Get.OperationID)
+
+	// The returned document is a copy.
+	doc.Info.Title = "Changed"
+	doc, _ = app.Spec()
+	assert.Equal(t, "Hooked", doc.Info.Title)
+}
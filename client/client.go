@@ -0,0 +1,306 @@
+// Package client is the runtime of clients generated by package clientgen. It
+// encodes request structs with the same tags simpleapi binds them with, and
+// decodes responses into the declared response types.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/sattvikc/go-simpleapi/reflection"
+)
+
+// Client sends requests to an API.
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+	// Header is added to every request, e.g. for an Authorization header.
+	Header http.Header
+}
+
+// New returns a client for the API at baseURL. A nil httpClient uses
+// http.DefaultClient.
+func New(baseURL string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{
+		BaseURL:    strings.TrimSuffix(baseURL, "/"),
+		HTTPClient: httpClient,
+		Header:     http.Header{},
+	}
+}
+
+// Response is a successful response with its body read.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// Decode decodes the JSON body into v. When v points to a response struct,
+// its header:"..." fields are read from the headers and its body:"json"
+// field from the body.
+func (r *Response) Decode(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("decode target must be a non-nil pointer, got %T", v)
+	}
+
+	fields, ok := reflection.GetResponseFields(rv.Type())
+	if !ok {
+		if len(r.Body) == 0 {
+			return nil
+		}
+		return json.Unmarshal(r.Body, v)
+	}
+
+	for _, field := range fields.Headers {
+		values := r.Header.Values(field.Tag.Get("header"))
+		target := rv.Elem().FieldByIndex(field.Index)
+		if target.Kind() == reflect.Slice {
+			for _, value := range values {
+				item := reflect.New(target.Type().Elem()).Elem()
+				if err := reflection.SetValue(item, value); err != nil {
+					return fmt.Errorf("header %s: %w", field.Tag.Get("header"), err)
+				}
+				target.Set(reflect.Append(target, item))
+			}
+			continue
+		}
+		if len(values) == 0 {
+			continue
+		}
+		if err := reflection.SetValue(target, values[0]); err != nil {
+			return fmt.Errorf("header %s: %w", field.Tag.Get("header"), err)
+		}
+	}
+
+	if len(r.Body) == 0 {
+		return nil
+	}
+	return json.Unmarshal(r.Body, rv.Elem().FieldByIndex(fields.Body.Index).Addr().Interface())
+}
+
+// Error is returned for responses with a status code of 400 or above.
+type Error struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+func (e *Error) Error() string {
+	var body struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(e.Body, &body) == nil && body.Error != "" {
+		return fmt.Sprintf("%d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), body.Error)
+	}
+	return fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// Decode decodes the JSON body of the error response into v.
+func (e *Error) Decode(v interface{}) error {
+	return json.Unmarshal(e.Body, v)
+}
+
+// Do sends a request to path, a route pattern such as /books/{id}. The
+// request structs fill in its path parameters, query, headers and body from
+// their path, query, header and body tags, as simpleapi binds them.
+func (c *Client) Do(ctx context.Context, method, path string, requests ...interface{}) (*Response, error) {
+	r := &encodedRequest{path: path, query: url.Values{}, header: http.Header{}}
+	for _, request := range requests {
+		v := reflect.ValueOf(request)
+		if v.Kind() == reflect.Ptr {
+			v = v.Elem()
+		}
+		if v.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("request must be a struct, got %T", request)
+		}
+		if err := r.encode(v); err != nil {
+			return nil, err
+		}
+	}
+
+	u := c.BaseURL + r.path
+	if len(r.query) > 0 {
+		u += "?" + r.query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, r.body)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range c.Header {
+		req.Header[name] = values
+	}
+	for name, values := range r.header {
+		req.Header[name] = values
+	}
+	if r.contentType != "" {
+		req.Header.Set("Content-Type", r.contentType)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 400 {
+		return nil, &Error{StatusCode: resp.StatusCode, Header: resp.Header, Body: body}
+	}
+	return &Response{StatusCode: resp.StatusCode, Header: resp.Header, Body: body}, nil
+}
+
+type encodedRequest struct {
+	path        string
+	query       url.Values
+	header      http.Header
+	body        io.Reader
+	contentType string
+}
+
+func (r *encodedRequest) encode(v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		value := v.Field(i)
+
+		switch body := field.Tag.Get("body"); {
+		case body == "json":
+			data, err := json.Marshal(value.Interface())
+			if err != nil {
+				return err
+			}
+			r.body = bytes.NewReader(data)
+			r.contentType = "application/json"
+
+		case body == "urlencoded":
+			form := url.Values{}
+			if err := eachFormValue(value, func(name string, v reflect.Value) error {
+				return addValue(form, name, v)
+			}); err != nil {
+				return err
+			}
+			r.body = strings.NewReader(form.Encode())
+			r.contentType = "application/x-www-form-urlencoded"
+
+		case body == "multipart":
+			if err := r.encodeMultipart(value); err != nil {
+				return err
+			}
+
+		case field.Type.Kind() == reflect.Struct:
+			if err := r.encode(value); err != nil {
+				return err
+			}
+
+		case field.Tag.Get("path") != "":
+			s, err := formatValue(value)
+			if err != nil {
+				return fmt.Errorf("path parameter %s: %w", field.Tag.Get("path"), err)
+			}
+			r.path = strings.ReplaceAll(r.path, "{"+field.Tag.Get("path")+"}", url.PathEscape(s))
+
+		case field.Tag.Get("query") != "":
+			if err := addValue(r.query, field.Tag.Get("query"), value); err != nil {
+				return err
+			}
+
+		case field.Tag.Get("header") != "":
+			if value.Kind() == reflect.Ptr && value.IsNil() {
+				continue
+			}
+			s, err := formatValue(value)
+			if err != nil {
+				return fmt.Errorf("header %s: %w", field.Tag.Get("header"), err)
+			}
+			r.header.Set(field.Tag.Get("header"), s)
+		}
+	}
+	return nil
+}
+
+var fileType = reflect.TypeOf((*multipart.File)(nil)).Elem()
+
+func (r *encodedRequest) encodeMultipart(v reflect.Value) error {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+
+	err := eachFormValue(v, func(name string, value reflect.Value) error {
+		if value.Type().ConvertibleTo(fileType) {
+			if value.IsNil() {
+				return nil
+			}
+			part, err := w.CreateFormFile(name, name)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(part, value.Interface().(io.Reader))
+			return err
+		}
+
+		if value.Kind() == reflect.Ptr && value.IsNil() {
+			return nil
+		}
+		s, err := formatValue(value)
+		if err != nil {
+			return fmt.Errorf("form field %s: %w", name, err)
+		}
+		return w.WriteField(name, s)
+	})
+	if err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	r.body = &buf
+	r.contentType = w.FormDataContentType()
+	return nil
+}
+
+func eachFormValue(v reflect.Value, fn func(name string, v reflect.Value) error) error {
+	for i := 0; i < v.NumField(); i++ {
+		if name := v.Type().Field(i).Tag.Get("form"); name != "" {
+			if err := fn(name, v.Field(i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// addValue adds v to values unless it is a nil pointer.
+func addValue(values url.Values, name string, v reflect.Value) error {
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		return nil
+	}
+	s, err := formatValue(v)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	values.Add(name, s)
+	return nil
+}
+
+func formatValue(v reflect.Value) (string, error) {
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	return reflection.FormatValue(v)
+}
@@ -0,0 +1,108 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sattvikc/go-simpleapi"
+	"github.com/sattvikc/go-simpleapi/client"
+	"github.com/stretchr/testify/assert"
+)
+
+type Novel struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	Lang  string `json:"lang"`
+}
+
+type GetNovel struct {
+	ID    string  `path:"id"`
+	Lang  *string `query:"lang"`
+	Token string  `header:"X-Token"`
+}
+
+type NovelResponse struct {
+	Modified time.Time `header:"Last-Modified"`
+	Body     Novel     `body:"json"`
+}
+
+func newServer(t *testing.T) *httptest.Server {
+	app := simpleapi.New()
+	app.Endpoint("/novels/{id}", func(e *simpleapi.Endpoint) interface{} {
+		e.WithResponse(200, NovelResponse{}, "Found").GET()
+		return func(ctx *simpleapi.Context, req GetNovel) error {
+			if req.Token != "secret" {
+				return simpleapi.NewHTTPError(401, errors.New("bad token"))
+			}
+			lang := "en"
+			if req.Lang != nil {
+				lang = *req.Lang
+			}
+			return ctx.JSON(200, NovelResponse{
+				Modified: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+				Body:     Novel{ID: req.ID, Title: "The Dispossessed", Lang: lang},
+			})
+		}
+	})
+	app.Endpoint("/novels", func(e *simpleapi.Endpoint) interface{} {
+		e.POST()
+		return func(ctx *simpleapi.Context, req struct {
+			Body Novel `body:"json"`
+		}) error {
+			return ctx.JSON(201, req.Body)
+		}
+	})
+
+	server := httptest.NewServer(app)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestDo(t *testing.T) {
+	c := client.New(newServer(t).URL, nil)
+	lang := "fr"
+
+	resp, err := c.Do(context.Background(), "GET", "/novels/{id}", GetNovel{ID: "a b", Lang: &lang, Token: "secret"})
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var novel NovelResponse
+	assert.NoError(t, resp.Decode(&novel))
+	assert.Equal(t, Novel{ID: "a b", Title: "The Dispossessed", Lang: "fr"}, novel.Body)
+	assert.True(t, novel.Modified.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)))
+
+	resp, err = c.Do(context.Background(), "POST", "/novels", struct {
+		Body Novel `body:"json"`
+	}{Body: Novel{ID: "1", Title: "Lathe"}})
+	assert.NoError(t, err)
+	assert.Equal(t, 201, resp.StatusCode)
+
+	var created Novel
+	assert.NoError(t, resp.Decode(&created))
+	assert.Equal(t, "Lathe", created.Title)
+}
+
+func TestDoReturnsErrorResponses(t *testing.T) {
+	c := client.New(newServer(t).URL, nil)
+
+	_, err := c.Do(context.Background(), "GET", "/novels/{id}", GetNovel{ID: "1", Token: "wrong"})
+
+	var apiErr *client.Error
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, 401, apiErr.StatusCode)
+	assert.Equal(t, "401 Unauthorized: bad token", err.Error())
+}
+
+func TestHeaderIsSentWithEveryRequest(t *testing.T) {
+	c := client.New(newServer(t).URL, nil)
+	c.Header.Set("X-Token", "secret")
+
+	resp, err := c.Do(context.Background(), "GET", "/novels/{id}", struct {
+		ID string `path:"id"`
+	}{ID: "1"})
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+}
@@ -0,0 +1,423 @@
+// Package clientgen generates a typed Go client from the endpoints registered
+// with an App. The client has one method per operation, taking the request
+// structs of the endpoint and returning its success response type, so that
+// consumers reuse the types the server binds and encodes.
+package clientgen
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/token"
+	"path"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/sattvikc/go-simpleapi"
+	"github.com/sattvikc/go-simpleapi/client"
+)
+
+const clientPackage = "github.com/sattvikc/go-simpleapi/client"
+
+// Config configures the generated package.
+type Config struct {
+	// Package is the name of the generated package.
+	Package string
+}
+
+// Generate returns the source of a client package for the routes of app.
+// WebSocket endpoints and endpoints that stream events are skipped. Request
+// and response types must be exported types of importable packages, or
+// literal types built from them. Operation ids that become the same method
+// name, or a member of client.Client such as Do, are reported as errors.
+func Generate(app *simpleapi.App, config Config) ([]byte, error) {
+	if !token.IsIdentifier(config.Package) {
+		return nil, fmt.Errorf("invalid package name %q", config.Package)
+	}
+
+	g := &generator{imports: map[string]string{}, aliases: map[string]bool{}, methods: clientMembers()}
+	g.importAs("context", "context")
+	g.importAs("net/http", "http")
+	g.importAs(clientPackage, "client")
+
+	var methods bytes.Buffer
+	for _, route := range app.Routes() {
+		if route.WebSocket || streams(route) {
+			continue
+		}
+		if err := g.method(&methods, route); err != nil {
+			return nil, fmt.Errorf("%s %s: %w", route.Method, route.Path, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by simpleapi clientgen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", config.Package)
+	g.writeImports(&buf)
+	buf.WriteString(`
+// Client calls the API. Responses with a status code of 400 or above are
+// returned as a *client.Error.
+type Client struct {
+	*client.Client
+}
+
+// New returns a client for the API at baseURL. A nil httpClient uses
+// http.DefaultClient.
+func New(baseURL string, httpClient *http.Client) *Client {
+	return &Client{Client: client.New(baseURL, httpClient)}
+}
+`)
+	buf.Write(methods.Bytes())
+
+	return format.Source(buf.Bytes())
+}
+
+func streams(route simpleapi.Route) bool {
+	for _, r := range route.Responses {
+		if r.ContentType == "text/event-stream" {
+			return true
+		}
+	}
+	return false
+}
+
+type generator struct {
+	// imports maps import paths to their names in the generated file.
+	imports map[string]string
+	aliases map[string]bool
+	// methods maps the names taken on the generated Client to what took
+	// them.
+	methods map[string]string
+}
+
+// clientMembers returns the fields and methods of the generated Client that
+// come from the embedded *client.Client, which operations must not shadow.
+func clientMembers() map[string]string {
+	members := map[string]string{"Client": "the embedded client.Client"}
+	t := reflect.TypeOf(&client.Client{})
+	for i := 0; i < t.NumMethod(); i++ {
+		members[t.Method(i).Name] = "method client.Client." + t.Method(i).Name
+	}
+	for i := 0; i < t.Elem().NumField(); i++ {
+		members[t.Elem().Field(i).Name] = "field client.Client." + t.Elem().Field(i).Name
+	}
+	return members
+}
+
+func (g *generator) importAs(importPath, name string) string {
+	if alias, ok := g.imports[importPath]; ok {
+		return alias
+	}
+
+	alias := name
+	for i := 2; g.aliases[alias] || token.IsKeyword(alias); i++ {
+		alias = fmt.Sprintf("%s%d", name, i)
+	}
+	g.imports[importPath] = alias
+	g.aliases[alias] = true
+	return alias
+}
+
+func (g *generator) writeImports(buf *bytes.Buffer) {
+	paths := make([]string, 0, len(g.imports))
+	for importPath := range g.imports {
+		paths = append(paths, importPath)
+	}
+	sort.Strings(paths)
+
+	// Standard library packages first, as goimports groups them.
+	sort.SliceStable(paths, func(i, j int) bool {
+		return isStd(paths[i]) && !isStd(paths[j])
+	})
+
+	buf.WriteString("import (\n")
+	for i, importPath := range paths {
+		if i > 0 && isStd(paths[i-1]) && !isStd(importPath) {
+			buf.WriteString("\n")
+		}
+		if alias := g.imports[importPath]; alias != path.Base(importPath) {
+			fmt.Fprintf(buf, "\t%s %q\n", alias, importPath)
+		} else {
+			fmt.Fprintf(buf, "\t%q\n", importPath)
+		}
+	}
+	buf.WriteString(")\n")
+}
+
+func isStd(importPath string) bool {
+	return !strings.Contains(strings.Split(importPath, "/")[0], ".")
+}
+
+func (g *generator) method(buf *bytes.Buffer, route simpleapi.Route) error {
+	name := exportedName(route.OperationID)
+	if name == "" {
+		return fmt.Errorf("operation id %q is not a valid method name", route.OperationID)
+	}
+	if taken, ok := g.methods[name]; ok {
+		return fmt.Errorf("operation id %q becomes method %s, which collides with %s; set another one with WithOperationID", route.OperationID, name, taken)
+	}
+	g.methods[name] = fmt.Sprintf("operation id %q", route.OperationID)
+
+	exprs := make([]string, len(route.Requests))
+	for i, t := range route.Requests {
+		expr, err := g.typeExpr(t)
+		if err != nil {
+			return err
+		}
+		exprs[i] = expr
+	}
+	result, err := g.success(route)
+	if err != nil {
+		return err
+	}
+
+	// Parameters must not shadow the imported packages used in the body.
+	used := map[string]bool{"ctx": true, "c": true, "resp": true, "err": true, "out": true}
+	for alias := range g.aliases {
+		used[alias] = true
+	}
+	params := []string{"ctx context.Context"}
+	args := []string{}
+	for i, t := range route.Requests {
+		param := paramName(t, used)
+		params = append(params, param+" "+exprs[i])
+		args = append(args, ", "+param)
+	}
+
+	buf.WriteString("\n")
+	writeDoc(buf, name, route)
+	call := fmt.Sprintf("c.Do(ctx, %q, %q%s)", route.Method, route.Path, strings.Join(args, ""))
+
+	switch {
+	case result == nil:
+		fmt.Fprintf(buf, "func (c *Client) %s(%s) error {\n", name, strings.Join(params, ", "))
+		fmt.Fprintf(buf, "\t_, err := %s\n\treturn err\n}\n", call)
+
+	case result.multiple:
+		fmt.Fprintf(buf, "func (c *Client) %s(%s) (*client.Response, error) {\n", name, strings.Join(params, ", "))
+		fmt.Fprintf(buf, "\treturn %s\n}\n", call)
+
+	case result.byValue:
+		fmt.Fprintf(buf, "func (c *Client) %s(%s) (%s, error) {\n", name, strings.Join(params, ", "), result.expr)
+		fmt.Fprintf(buf, "\tresp, err := %s\n\tif err != nil {\n\t\treturn nil, err\n\t}\n", call)
+		fmt.Fprintf(buf, "\tvar out %s\n\tif err := resp.Decode(&out); err != nil {\n\t\treturn nil, err\n\t}\n\treturn out, nil\n}\n", result.expr)
+
+	default:
+		fmt.Fprintf(buf, "func (c *Client) %s(%s) (*%s, error) {\n", name, strings.Join(params, ", "), result.expr)
+		fmt.Fprintf(buf, "\tresp, err := %s\n\tif err != nil {\n\t\treturn nil, err\n\t}\n", call)
+		fmt.Fprintf(buf, "\tout := new(%s)\n\tif err := resp.Decode(out); err != nil {\n\t\treturn nil, err\n\t}\n\treturn out, nil\n}\n", result.expr)
+	}
+	return nil
+}
+
+func writeDoc(buf *bytes.Buffer, name string, route simpleapi.Route) {
+	summary := strings.TrimSuffix(strings.TrimSpace(route.Summary), ".")
+	if summary == "" {
+		fmt.Fprintf(buf, "// %s calls %s %s.\n", name, route.Method, route.Path)
+	} else {
+		fmt.Fprintf(buf, "// %s calls %s %s: %s.\n", name, route.Method, route.Path, summary)
+	}
+	for _, line := range strings.Split(strings.TrimSpace(route.Description), "\n") {
+		if line != "" {
+			fmt.Fprintf(buf, "// %s\n", line)
+		}
+	}
+	if route.Deprecated {
+		buf.WriteString("//\n// Deprecated: the operation is deprecated by the API.\n")
+	}
+}
+
+type successType struct {
+	expr string
+	// byValue is set for slices, maps and interfaces, which are returned
+	// without a pointer.
+	byValue bool
+	// multiple is set when the operation declares several success types,
+	// in which case the response is returned for the caller to decode.
+	multiple bool
+}
+
+// success returns the type decoded from 2xx JSON responses, or nil when
+// none has a body.
+func (g *generator) success(route simpleapi.Route) (*successType, error) {
+	var types []reflect.Type
+	for _, r := range route.Responses {
+		if r.Code < 200 || r.Code > 299 || r.Type == nil || r.ContentType != "application/json" {
+			continue
+		}
+		t := r.Type
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if len(types) == 0 || types[0] != t {
+			types = append(types, t)
+		}
+	}
+
+	switch {
+	case len(types) == 0:
+		return nil, nil
+	case len(types) > 1:
+		return &successType{multiple: true}, nil
+	}
+
+	expr, err := g.typeExpr(types[0])
+	if err != nil {
+		return nil, err
+	}
+	switch types[0].Kind() {
+	case reflect.Slice, reflect.Map, reflect.Interface:
+		return &successType{expr: expr, byValue: true}, nil
+	default:
+		return &successType{expr: expr}, nil
+	}
+}
+
+// typeExpr returns the Go expression for t, importing its package.
+func (g *generator) typeExpr(t reflect.Type) (string, error) {
+	if t.Name() != "" {
+		if t.PkgPath() == "" {
+			return t.Name(), nil
+		}
+		if t.PkgPath() == "main" {
+			return "", fmt.Errorf("type %s is declared in package main and cannot be imported", t)
+		}
+		if strings.Contains(t.Name(), "[") {
+			return "", fmt.Errorf("generic type %s is not supported", t)
+		}
+		if !ast.IsExported(t.Name()) {
+			return "", fmt.Errorf("type %s is not exported", t)
+		}
+		return g.importAs(t.PkgPath(), packageName(t.PkgPath())) + "." + t.Name(), nil
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		elem, err := g.typeExpr(t.Elem())
+		return "*" + elem, err
+
+	case reflect.Slice:
+		elem, err := g.typeExpr(t.Elem())
+		return "[]" + elem, err
+
+	case reflect.Array:
+		elem, err := g.typeExpr(t.Elem())
+		return fmt.Sprintf("[%d]%s", t.Len(), elem), err
+
+	case reflect.Map:
+		key, err := g.typeExpr(t.Key())
+		if err != nil {
+			return "", err
+		}
+		elem, err := g.typeExpr(t.Elem())
+		return "map[" + key + "]" + elem, err
+
+	case reflect.Interface:
+		if t.NumMethod() > 0 {
+			return "", fmt.Errorf("interface type %s is not supported", t)
+		}
+		return "interface{}", nil
+
+	case reflect.Struct:
+		var b strings.Builder
+		b.WriteString("struct {\n")
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				return "", fmt.Errorf("struct field %s is not exported", field.Name)
+			}
+			expr, err := g.typeExpr(field.Type)
+			if err != nil {
+				return "", err
+			}
+			if field.Anonymous {
+				b.WriteString(expr)
+			} else {
+				b.WriteString(field.Name + " " + expr)
+			}
+			if field.Tag != "" {
+				b.WriteString(" " + quoteTag(string(field.Tag)))
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("}")
+		return b.String(), nil
+
+	default:
+		return "", fmt.Errorf("type %s is not supported", t)
+	}
+}
+
+// packageName guesses the name of a package from its import path, dropping
+// major version suffixes and go- prefixes as goimports does.
+func packageName(importPath string) string {
+	name := path.Base(importPath)
+	if strings.HasPrefix(name, "v") && len(name) > 1 {
+		if _, err := strconv.Atoi(name[1:]); err == nil {
+			name = path.Base(path.Dir(importPath))
+		}
+	}
+	name = strings.TrimPrefix(name, "go-")
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			return r
+		}
+		return -1
+	}, name)
+	if name == "" || !unicode.IsLetter(rune(name[0])) {
+		name = "pkg" + name
+	}
+	return name
+}
+
+func quoteTag(tag string) string {
+	if !strings.Contains(tag, "`") {
+		return "`" + tag + "`"
+	}
+	return strconv.Quote(tag)
+}
+
+// exportedName turns an operation id such as getBook or GET_books-id into
+// a method name.
+func exportedName(id string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range id {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if b.Len() == 0 && unicode.IsDigit(r) {
+			b.WriteString("Op")
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// paramName names a request parameter after its type, or "params" for
+// literal structs.
+func paramName(t reflect.Type, used map[string]bool) string {
+	name := "params"
+	if t.Name() != "" {
+		name = string(unicode.ToLower(rune(t.Name()[0]))) + t.Name()[1:]
+	}
+	if token.IsKeyword(name) {
+		name += "Params"
+	}
+
+	candidate := name
+	for i := 2; used[candidate]; i++ {
+		candidate = fmt.Sprintf("%s%d", name, i)
+	}
+	used[candidate] = true
+	return candidate
+}
@@ -0,0 +1,109 @@
+package clientgen_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/sattvikc/go-simpleapi"
+	"github.com/sattvikc/go-simpleapi/clientgen"
+	"github.com/sattvikc/go-simpleapi/example/books"
+	"github.com/stretchr/testify/assert"
+)
+
+type GetNovel struct {
+	ID    string `path:"id"`
+	Lang  string `query:"lang"`
+	Token string `header:"X-Token"`
+}
+
+type Novel struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+type unexported struct {
+	ID string `path:"id"`
+}
+
+func TestGenerate(t *testing.T) {
+	app := simpleapi.New()
+	app.Endpoint("/novels/{id}", func(e *simpleapi.Endpoint) interface{} {
+		e.WithSummary("Get a novel.").
+			WithResponse(200, &Novel{}, "Found").
+			WithResponse(404, nil, "Not found").
+			GET()
+		return func(ctx *simpleapi.Context, req GetNovel) error { return nil }
+	})
+	app.Endpoint("/novels", func(e *simpleapi.Endpoint) interface{} {
+		e.WithOperationID("list-novels").
+			WithResponse(200, []Novel{}, "Novels").
+			Deprecated().
+			GET()
+		return func(ctx *simpleapi.Context) error { return nil }
+	})
+	app.Endpoint("/novels/{id}", func(e *simpleapi.Endpoint) interface{} {
+		e.WithOperationID("deleteNovel").
+			WithResponse(204, nil, "Deleted").
+			DELETE()
+		return func(ctx *simpleapi.Context, req struct {
+			ID string `path:"id"`
+		}) error {
+			return nil
+		}
+	})
+	app.Endpoint("/events", func(e *simpleapi.Endpoint) interface{} {
+		e.WithEventStream(200, Novel{}, "Novels as they are published").GET()
+		return func(ctx *simpleapi.Context) error { return nil }
+	})
+
+	src, err := clientgen.Generate(app, clientgen.Config{Package: "novels"})
+	assert.NoError(t, err)
+
+	code := string(src)
+	assert.Contains(t, code, "package novels\n")
+	assert.Contains(t, code, "// GetNovelsId calls GET /novels/{id}: Get a novel.\n")
+	assert.Contains(t, code, "func (c *Client) GetNovelsId(ctx context.Context, getNovel clientgen_test.GetNovel) (*clientgen_test.Novel, error) {")
+	assert.Contains(t, code, "// Deprecated: the operation is deprecated by the API.\nfunc (c *Client) ListNovels(ctx context.Context) ([]clientgen_test.Novel, error) {")
+	assert.Contains(t, code, "func (c *Client) DeleteNovel(ctx context.Context, params struct {\n\tID string `path:\"id\"`\n}) error {")
+	assert.NotContains(t, code, "/events")
+}
+
+func TestGenerateRejectsUnexportedTypes(t *testing.T) {
+	app := simpleapi.New()
+	app.Endpoint("/novels/{id}", func(e *simpleapi.Endpoint) interface{} {
+		e.GET()
+		return func(ctx *simpleapi.Context, req unexported) error { return nil }
+	})
+
+	_, err := clientgen.Generate(app, clientgen.Config{Package: "novels"})
+	assert.EqualError(t, err, "GET /novels/{id}: type clientgen_test.unexported is not exported")
+}
+
+func TestGenerateRejectsCollidingMethods(t *testing.T) {
+	endpoint := func(path, operationID string) (string, func(e *simpleapi.Endpoint) interface{}) {
+		return path, func(e *simpleapi.Endpoint) interface{} {
+			e.WithOperationID(operationID).GET()
+			return func(ctx *simpleapi.Context) error { return nil }
+		}
+	}
+
+	app := simpleapi.New()
+	app.Endpoint(endpoint("/books", "getBook"))
+	app.Endpoint(endpoint("/books/{id}", "get-book"))
+	_, err := clientgen.Generate(app, clientgen.Config{Package: "books"})
+	assert.EqualError(t, err, `GET /books/{id}: operation id "get-book" becomes method GetBook, which collides with operation id "getBook"; set another one with WithOperationID`)
+
+	app = simpleapi.New()
+	app.Endpoint(endpoint("/do", "do"))
+	_, err = clientgen.Generate(app, clientgen.Config{Package: "books"})
+	assert.EqualError(t, err, `GET /do: operation id "do" becomes method Do, which collides with method client.Client.Do; set another one with WithOperationID`)
+}
+
+func TestExampleClientIsUpToDate(t *testing.T) {
+	src, err := clientgen.Generate(books.New(), clientgen.Config{Package: "booksclient"})
+	assert.NoError(t, err)
+
+	expected, err := os.ReadFile("../example/booksclient/client.go")
+	assert.NoError(t, err)
+	assert.Equal(t, string(expected), string(src), "run go generate ./example/booksclient")
+}
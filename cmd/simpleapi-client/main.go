@@ -0,0 +1,36 @@
+// Command simpleapi-client generates a Go client for the example books API
+// from its registered endpoints. Copy it next to your own API and replace
+// books.New with the function that builds your App:
+//
+//	go run ./cmd/simpleapi-client -package booksclient -o client.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/sattvikc/go-simpleapi/clientgen"
+	"github.com/sattvikc/go-simpleapi/example/books"
+)
+
+func main() {
+	pkg := flag.String("package", "client", "name of the generated package")
+	output := flag.String("o", "", "output file, standard output if empty")
+	flag.Parse()
+
+	src, err := clientgen.Generate(books.New(), clientgen.Config{Package: *pkg})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if *output == "" {
+		os.Stdout.Write(src)
+		return
+	}
+	if err := os.WriteFile(*output, src, 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
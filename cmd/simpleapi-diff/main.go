@@ -0,0 +1,62 @@
+// Command simpleapi-diff compares two versions of an OpenAPI document, as
+// written by App.WriteSpec, and lists the changes. It exits with status 1
+// when a change is breaking, so that CI can reject it:
+//
+//	git show main:openapi.yaml > /tmp/base.yaml
+//	go run ./cmd/simpleapi-diff /tmp/base.yaml openapi.yaml
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/sattvikc/go-simpleapi/openapi"
+)
+
+func main() {
+	breakingOnly := flag.Bool("breaking", false, "only list breaking changes")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: simpleapi-diff [-breaking] base revision")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	base, err := load(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	revision, err := load(flag.Arg(1))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	changes := openapi.Diff(base, revision)
+	for _, change := range changes {
+		if change.Breaking || !*breakingOnly {
+			fmt.Println(change)
+		}
+	}
+
+	if openapi.HasBreaking(changes) {
+		os.Exit(1)
+	}
+}
+
+func load(path string) (*openapi.Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := openapi.Load(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return doc, nil
+}
@@ -0,0 +1,36 @@
+// Command simpleapi-spec writes the OpenAPI document of the example books API
+// without starting a server. Copy it next to your own API and replace
+// books.New with the function that builds your App, then commit the output:
+//
+//	go run ./cmd/simpleapi-spec -format yaml -o openapi.yaml
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/sattvikc/go-simpleapi/example/books"
+)
+
+func main() {
+	format := flag.String("format", "json", "output format, json or yaml")
+	output := flag.String("o", "", "output file, standard output if empty")
+	flag.Parse()
+
+	var buf bytes.Buffer
+	if err := books.New().WriteSpec(&buf, *format); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if *output == "" {
+		os.Stdout.Write(buf.Bytes())
+		return
+	}
+	if err := os.WriteFile(*output, buf.Bytes(), 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
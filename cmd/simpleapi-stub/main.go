@@ -0,0 +1,58 @@
+// Command simpleapi-stub generates simpleapi server stubs from an OpenAPI 3
+// document in JSON or YAML, for APIs whose specification comes first:
+//
+//	go run ./cmd/simpleapi-stub -package petstore -o stubs.go openapi.yaml
+//
+// The stubs declare the documented types, requests and responses. The
+// handlers return simpleapi.ErrNotImplemented until they are written, so
+// they answer with mock responses under simpleapi.WithMocks.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/sattvikc/go-simpleapi/openapi"
+	"github.com/sattvikc/go-simpleapi/stubgen"
+)
+
+func main() {
+	pkg := flag.String("package", "api", "name of the generated package")
+	output := flag.String("o", "", "output file, standard output if empty")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: simpleapi-stub [-package name] [-o file] openapi.yaml")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	data, err := os.ReadFile(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	doc, err := openapi.Load(data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", flag.Arg(0), err)
+		os.Exit(1)
+	}
+
+	src, err := stubgen.Generate(doc, stubgen.Config{Package: *pkg})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if *output == "" {
+		os.Stdout.Write(src)
+		return
+	}
+	if err := os.WriteFile(*output, src, 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
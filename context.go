@@ -1,46 +1,216 @@
 package simpleapi
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"reflect"
+	"time"
 
 	"github.com/sattvikc/go-simpleapi/handler"
+	"github.com/sattvikc/go-simpleapi/reflection"
 	"github.com/sattvikc/go-simpleapi/router"
+	"github.com/sattvikc/go-simpleapi/websocket"
 )
 
+var wsConnType = reflect.TypeOf((*websocket.Conn)(nil))
+
 type Context struct {
+	app      *App
 	Request  *http.Request
-	Response http.ResponseWriter
+	Response ResponseWriter
 	params   router.Params
 	next     *handler.Handler
+	ws       *websocket.Conn
+	values   map[string]interface{}
+	aborted  bool
+	after    []func(ctx *Context, err error)
+	provided map[reflect.Type]reflect.Value
 }
 
+var _ context.Context = (*Context)(nil)
+
+// Next runs the next handler in the chain. It does nothing once the chain has
+// been aborted.
 func (c *Context) Next() error {
-	if !c.next.HasNext() {
+	if c.aborted || !c.next.HasNext() {
 		return nil
 	}
 
 	nextHandler := c.next.Get()
 
-	return nextHandler.Invoke(c, c.Request, c.params)
+	return nextHandler.Invoke(c, c.Request, c.params, c.resolve)
+}
+
+// Abort stops the remaining handlers in the chain from running. Handlers that
+// already called Next still get to finish.
+func (c *Context) Abort() {
+	c.aborted = true
+}
+
+// AbortWithError aborts the chain, responds with status and the error message
+// unless a response was already written, and returns the error so that it can
+// be returned from the handler.
+func (c *Context) AbortWithError(status int, err error) error {
+	c.Abort()
+	httpErr := NewHTTPError(status, err)
+	writeError(c, httpErr)
+	return httpErr
+}
+
+// IsAborted reports whether Abort or AbortWithError has been called.
+func (c *Context) IsAborted() bool {
+	return c.aborted
+}
+
+// After registers fn to run once the whole chain has completed, with the error
+// returned by the chain. Hooks run in reverse order of registration.
+func (c *Context) After(fn func(ctx *Context, err error)) {
+	c.after = append(c.after, fn)
+}
+
+func (c *Context) runAfter(err error) {
+	for i := len(c.after) - 1; i >= 0; i-- {
+		c.after[i](c, err)
+	}
+}
+
+// Set stores a value for the lifetime of the request, making it available to
+// the handlers later in the chain.
+func (c *Context) Set(key string, value interface{}) {
+	if c.values == nil {
+		c.values = map[string]interface{}{}
+	}
+	c.values[key] = value
+}
+
+// Get returns a value stored with Set.
+func (c *Context) Get(key string) (interface{}, bool) {
+	value, ok := c.values[key]
+	return value, ok
+}
+
+// Value returns the value stored with Set under key, typed as T. The second
+// result is false if the key is missing or holds a different type.
+func Value[T any](c *Context, key string) (T, bool) {
+	value, ok := c.values[key].(T)
+	return value, ok
+}
+
+// SetContext replaces the context of the request, e.g. to add a deadline
+// that the remaining handlers should observe.
+func (c *Context) SetContext(ctx context.Context) {
+	c.Request = c.Request.WithContext(ctx)
+}
+
+// Deadline, Done and Err delegate to the request context, so a *Context can
+// be passed wherever a context.Context is expected.
+func (c *Context) Deadline() (time.Time, bool) {
+	return c.Request.Context().Deadline()
+}
+
+func (c *Context) Done() <-chan struct{} {
+	return c.Request.Context().Done()
+}
+
+func (c *Context) Err() error {
+	return c.Request.Context().Err()
 }
 
+// Value returns the value stored with Set if key is a string, and otherwise
+// looks the key up in the request context.
+func (c *Context) Value(key interface{}) interface{} {
+	if k, ok := key.(string); ok {
+		if value, ok := c.values[k]; ok {
+			return value
+		}
+	}
+	return c.Request.Context().Value(key)
+}
+
+// WebSocket upgrades the request to a websocket connection. The connection is
+// created once per request and closed when the handler chain returns.
+func (c *Context) WebSocket() (*websocket.Conn, error) {
+	if c.ws != nil {
+		return c.ws, nil
+	}
+
+	conn, err := c.app.upgrader.Upgrade(c.Response, c.Request)
+	if err != nil {
+		return nil, err
+	}
+	c.ws = conn
+	return conn, nil
+}
+
+func (c *Context) resolve(t reflect.Type) (reflect.Value, error) {
+	if t == wsConnType {
+		conn, err := c.WebSocket()
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(conn), nil
+	}
+
+	if value, ok := c.provided[t]; ok {
+		if !value.IsValid() {
+			return reflect.Value{}, fmt.Errorf("dependency cycle while resolving %v", t)
+		}
+		return value, nil
+	}
+
+	var p *handler.Provider
+	if c.app != nil {
+		p = c.app.provider(t)
+	}
+	if p == nil {
+		return reflect.Value{}, fmt.Errorf("no provider registered for %v", t)
+	}
+
+	if c.provided == nil {
+		c.provided = map[reflect.Type]reflect.Value{}
+	}
+	c.provided[t] = reflect.Value{}
+
+	value, err := p.Call(c, c.Request, c.params, c.resolve)
+	if err != nil {
+		delete(c.provided, t)
+		return reflect.Value{}, err
+	}
+	c.provided[t] = value
+	return value, nil
+}
+
+// JSON writes data as a JSON response. When data is a response struct, that
+// is a struct with a body:"json" field, its header:"..." fields are sent as
+// headers and only the body field is encoded.
 func (c *Context) JSON(status int, data interface{}) error {
-	c.Response.Header().Set("Content-Type", "application/json")
-	c.Response.WriteHeader(status)
+	if v := reflect.ValueOf(data); v.IsValid() && !(v.Kind() == reflect.Ptr && v.IsNil()) {
+		if _, ok := reflection.GetResponseFields(v.Type()); ok {
+			body, err := reflection.WriteResponseHeaders(c.Response.Header(), v)
+			if err != nil {
+				return err
+			}
+			data = body.Interface()
+		}
+	}
+
 	dataBytes, err := json.Marshal(data)
 	if err != nil {
 		return err
 	}
+	c.Response.Header().Set("Content-Type", "application/json")
 	c.Response.Header().Set("Content-Length", fmt.Sprint(len(dataBytes)))
-	c.Response.Write(dataBytes)
-	return nil
+	c.Response.WriteHeader(status)
+	_, err = c.Response.Write(dataBytes)
+	return err
 }
 
 func (c *Context) HTML(status int, html string) error {
 	c.Response.Header().Set("Content-Type", "text/html")
+	c.Response.Header().Set("Content-Length", fmt.Sprint(len(html)))
 	c.Response.WriteHeader(status)
-	c.Response.Write([]byte(html))
-	return nil
+	_, err := c.Response.Write([]byte(html))
+	return err
 }
@@ -9,11 +9,29 @@ type Endpoint struct {
 	handlers         []interface{}
 	handlerInstances *handler.Handler
 	tags             []string
-	responseTypes    []struct {
-		code        int
-		response    interface{}
-		description string
-	}
+	websocket        bool
+	after            []func(ctx *Context, err error)
+	responseTypes    []responseType
+	discriminators   map[int]string
+	summary          string
+	description      string
+	operationID      string
+	deprecated       bool
+	externalDocs     *ExternalDocs
+	security         []SecurityRequirement
+	builderName      string
+	// mocked is set when the last handler is replaced by a mock.
+	mocked bool
+}
+
+type responseType struct {
+	code        int
+	response    interface{}
+	description string
+	contentType string
+	variant     string
+	examples    []responseExample
+	headers     []responseHeader
 }
 
 func (e *Endpoint) WithTag(tag string) *Endpoint {
@@ -21,12 +39,66 @@ func (e *Endpoint) WithTag(tag string) *Endpoint {
 	return e
 }
 
-func (e *Endpoint) WithResponse(code int, response interface{}, description string) *Endpoint {
-	e.responseTypes = append(e.responseTypes, struct {
-		code        int
-		response    interface{}
-		description string
-	}{code: code, response: response, description: description})
+// WithSummary sets a short summary of what the operation does.
+func (e *Endpoint) WithSummary(summary string) *Endpoint {
+	e.summary = summary
+	return e
+}
+
+// WithDescription sets a longer explanation of the operation. CommonMark is
+// allowed.
+func (e *Endpoint) WithDescription(description string) *Endpoint {
+	e.description = description
+	return e
+}
+
+// WithOperationID sets the operationId, which client generators use as the
+// method name. It must be unique within the App. When not set, it is derived
+// from the name of the last handler builder, e.g. createBook.
+func (e *Endpoint) WithOperationID(id string) *Endpoint {
+	e.operationID = id
+	return e
+}
+
+// Deprecated marks the operation as deprecated.
+func (e *Endpoint) Deprecated() *Endpoint {
+	e.deprecated = true
+	return e
+}
+
+// WithExternalDocs links additional documentation for the operation.
+func (e *Endpoint) WithExternalDocs(url, description string) *Endpoint {
+	e.externalDocs = &ExternalDocs{URL: url, Description: description}
+	return e
+}
+
+// WithResponse documents a JSON response. Several responses may be declared
+// for the same code; their bodies are documented as alternatives with oneOf.
+func (e *Endpoint) WithResponse(code int, response interface{}, description string, opts ...ResponseOption) *Endpoint {
+	return e.addResponse(responseType{
+		code:        code,
+		response:    response,
+		description: description,
+		contentType: "application/json",
+	}, opts)
+}
+
+// WithEventStream documents a text/event-stream response whose events carry
+// data of the given type. Handlers pass the code to Context.SSE.
+func (e *Endpoint) WithEventStream(code int, event interface{}, description string, opts ...ResponseOption) *Endpoint {
+	return e.addResponse(responseType{
+		code:        code,
+		response:    event,
+		description: description,
+		contentType: "text/event-stream",
+	}, opts)
+}
+
+func (e *Endpoint) addResponse(r responseType, opts []ResponseOption) *Endpoint {
+	for _, opt := range opts {
+		opt(&r)
+	}
+	e.responseTypes = append(e.responseTypes, r)
 	return e
 }
 
@@ -50,13 +122,38 @@ func (e *Endpoint) DELETE() *Endpoint {
 	return e
 }
 
-func (e *Endpoint) register() error {
-	e.app.addToSwagger(e.path, e.handlerInstances, e.method, e.tags, e.responseTypes)
+// After registers fn to run after the handler chain of every request to this
+// endpoint has completed. See Context.After.
+func (e *Endpoint) After(fn func(ctx *Context, err error)) *Endpoint {
+	e.after = append(e.after, fn)
+	return e
+}
 
-	err := e.app.AddHandler(e.path, e.method, e.handlers...)
-	if err != nil {
-		return err
+// WebSocket makes the endpoint accept websocket upgrades. The request struct
+// is bound from the upgrade request, and a handler parameter of type
+// *websocket.Conn receives the established connection. Upgrades from other
+// origins are refused unless allowed with WithWebSocketOrigins.
+func (e *Endpoint) WebSocket() *Endpoint {
+	e.method = "get"
+	e.websocket = true
+	return e
+}
+
+func (e *Endpoint) register() {
+	if e.websocket {
+		e.responseTypes = append(e.responseTypes, responseType{
+			code:        101,
+			description: "Switching Protocols",
+		})
 	}
 
-	return nil
+	e.app.mu.Lock()
+	defer e.app.mu.Unlock()
+
+	e.app.checkParams(e)
+	e.operationID = e.app.operationID(e)
+	e.app.endpoints = append(e.app.endpoints, e)
+	e.app.routes[e.handlerInstances] = e
+	e.app.r.Add(e.path, e.method, e.handlerInstances, "")
+	e.app.spec = nil
 }
@@ -0,0 +1,66 @@
+package simpleapi
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/sattvikc/go-simpleapi/handler"
+)
+
+// HTTPError is an error that carries the status code to respond with.
+type HTTPError struct {
+	Status int
+	Err    error
+}
+
+func NewHTTPError(status int, err error) *HTTPError {
+	return &HTTPError{Status: status, Err: err}
+}
+
+func (e *HTTPError) Error() string {
+	if e.Err == nil {
+		return http.StatusText(e.Status)
+	}
+	return e.Err.Error()
+}
+
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+// writeError responds with the error as JSON unless a response has already
+// been written. The message of errors that are not an HTTPError or a
+// BindError may reveal internals, so it is logged and the client only gets
+// the status text.
+func writeError(ctx *Context, err error) {
+	status := errorStatus(err)
+	message := err.Error()
+	var httpErr *HTTPError
+	if status == http.StatusInternalServerError && !errors.As(err, &httpErr) {
+		log.Println("simpleapi:", err)
+		message = http.StatusText(status)
+	}
+
+	if ctx.Response.Written() {
+		return
+	}
+	ctx.JSON(status, map[string]interface{}{
+		"error": message,
+	})
+}
+
+// errorStatus returns the status code to respond to err with. Requests that
+// cannot be bound are unprocessable, other errors are internal unless they
+// are an HTTPError.
+func errorStatus(err error) int {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.Status
+	}
+	var bindErr *handler.BindError
+	if errors.As(err, &bindErr) {
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,94 @@
+// Package books is a small example API, importable so that its OpenAPI
+// document can be generated without starting the server.
+package books
+
+import (
+	"fmt"
+
+	"github.com/sattvikc/go-simpleapi"
+)
+
+type CreateBook struct {
+	Body struct {
+		Title  string `json:"title"`
+		ISBN   string `json:"isbn"`
+		Author string `json:"author"`
+	} `body:"json"`
+}
+
+type Book struct {
+	Id     string `json:"id"`
+	Title  string `json:"title"`
+	ISBN   string `json:"isbn"`
+	Author string `json:"author"`
+}
+
+type CreateBookOK struct {
+	Status string `json:"status"`
+	Book   Book   `json:"book"`
+}
+
+type CreateBookExists struct {
+	Status string `json:"status"`
+	Book   Book   `json:"book"`
+}
+
+func createBook(e *simpleapi.Endpoint) interface{} {
+	e.WithTag("Books").
+		WithSummary("Create a book").
+		WithResponse(200, CreateBookOK{}, "Book created",
+			simpleapi.ResponseVariant("OK"),
+			simpleapi.ResponseHeader("Location", "", "URL of the book"),
+			simpleapi.ResponseExample("created", CreateBookOK{
+				Status: "OK",
+				Book:   Book{Id: "1", Title: "The Dispossessed", ISBN: "9780061054884", Author: "Ursula K. Le Guin"},
+			})).
+		WithResponse(200, CreateBookExists{}, "Book already exists",
+			simpleapi.ResponseVariant("EXISTS")).
+		WithDiscriminator(200, "status").
+		POST()
+
+	return func(ctx *simpleapi.Context, req CreateBook) error {
+		fmt.Printf("Request: %+v", req)
+
+		ctx.JSON(200, map[string]interface{}{
+			"status": "OK",
+		})
+
+		return nil
+	}
+}
+
+func withAuth(e *simpleapi.Endpoint) interface{} {
+	type Unauthorised struct {
+		Reason string `json:"reason"`
+	}
+
+	e.WithSecurity("bearer").
+		WithResponse(401, Unauthorised{}, "Unauthorised")
+
+	return func(ctx *simpleapi.Context, headers struct {
+		Authorization string `header:"Authorization"`
+	}) error {
+		fmt.Println("Authorization:", headers.Authorization)
+		ctx.Abort()
+		return ctx.JSON(401, Unauthorised{
+			Reason: "Token expired",
+		})
+	}
+}
+
+// New returns the books API.
+func New() *simpleapi.App {
+	app := simpleapi.New(
+		simpleapi.WithInfo(simpleapi.Info{
+			Title:       "Books",
+			Description: "A small book store",
+			Version:     "1.0.0",
+		}),
+		simpleapi.WithTags(simpleapi.Tag{Name: "Books", Description: "Create and look up books"}),
+		simpleapi.WithSecurityScheme("bearer", simpleapi.HTTPBearer("JWT")),
+	)
+	app.Endpoint("/books", withAuth, createBook)
+	return app
+}
@@ -0,0 +1,5 @@
+// Package booksclient is the client of the example books API, generated from
+// its registered endpoints.
+package booksclient
+
+//go:generate go run ../../cmd/simpleapi-client -package booksclient -o client.go
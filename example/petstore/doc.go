@@ -0,0 +1,6 @@
+// Package petstore is generated by simpleapi-stub from the OpenAPI document
+// next to it, as a starting point for an API whose specification was
+// written first.
+package petstore
+
+//go:generate go run ../../cmd/simpleapi-stub -package petstore -o stubs.go openapi.yaml
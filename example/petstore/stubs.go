@@ -0,0 +1,185 @@
+// Stubs generated by simpleapi stubgen from "Petstore" 2.1.0; implement the handlers.
+//
+// Note: GET /pets: cookie parameter session is not bound by simpleapi.
+//
+// Note: GET /pets: default response is skipped, simpleapi declares responses by status code.
+//
+// Note: GET /pets/{petId}: 404 response of type text/plain is declared without a body.
+//
+// Note: PATCH /pets/{petId} is skipped, simpleapi does not register PATCH endpoints.
+//
+// Note: arrays and maps become Go slices and maps, which are documented as nullable because nil ones are encoded as null.
+
+package petstore
+
+import (
+	"mime/multipart"
+	"time"
+
+	"github.com/sattvikc/go-simpleapi"
+)
+
+// Options returns the options describing the API, to pass to simpleapi.New.
+func Options() []simpleapi.Option {
+	return []simpleapi.Option{
+		simpleapi.WithInfo(simpleapi.Info{Title: "Petstore", Description: "Pets for sale", Version: "2.1.0"}),
+		simpleapi.WithServers(simpleapi.Server{URL: "https://petstore.example.com/v2"}),
+		simpleapi.WithTags(simpleapi.Tag{Name: "pets", Description: "Everything about pets"}),
+		simpleapi.WithSecurityScheme("api_key", simpleapi.APIKeyHeader("X-API-Key")),
+		simpleapi.WithSecurityScheme("bearer", simpleapi.HTTPBearer("JWT")),
+		simpleapi.WithSecurity("bearer"),
+	}
+}
+
+// Register registers the endpoints of the API with app.
+func Register(app *simpleapi.App) {
+	app.Endpoint("/events", PetEvents)
+	app.Endpoint("/pets", ListPets)
+	app.Endpoint("/pets", CreatePet)
+	app.Endpoint("/pets/{petId}", GetPet)
+	app.Endpoint("/pets/{petId}", DeletePetsPetID)
+	app.Endpoint("/pets/{petId}/photo", UploadPhoto)
+}
+
+type Cat struct {
+	Pet
+	Kind  string `json:"kind,omitempty"`
+	Lives int32  `json:"lives,omitempty"`
+}
+
+type Dog struct {
+	Pet
+	Barks bool   `json:"barks,omitempty"`
+	Kind  string `json:"kind,omitempty"`
+}
+
+type Owner struct {
+	Email string `json:"email" format:"email"`
+}
+
+// A pet for sale.
+type Pet struct {
+	Attributes map[string]int `json:"attributes,omitempty"`
+	Born       *time.Time     `json:"born,omitempty"`
+	ID         int64          `json:"id"`
+	Name       string         `json:"name" example:"Rex"`
+	Owner      *Owner         `json:"owner,omitempty"`
+	Status     string         `json:"status" doc:"Availability in the store" enum:"available,pending,sold"`
+	Tags       []string       `json:"tags,omitempty"`
+}
+
+// ListPetsRequest is the request of ListPets.
+type ListPetsRequest struct {
+	Limit  *int32 `query:"limit" doc:"How many pets to return"`
+	Status string `query:"status" enum:"available,pending,sold"`
+}
+
+// CreatePetRequest is the request of CreatePet.
+type CreatePetRequest struct {
+	Body struct {
+		Name   string `json:"name"`
+		Status string `json:"status,omitempty"`
+	} `body:"json"`
+}
+
+// CreatePet201Response is the 201 response of CreatePet.
+type CreatePet201Response struct {
+	ID int64 `json:"id,omitempty"`
+}
+
+// GetPetRequest is the request of GetPet.
+type GetPetRequest struct {
+	PetID      int64   `path:"petId"`
+	XRequestID *string `header:"X-Request-ID" format:"uuid"`
+}
+
+// DeletePetsPetIDRequest is the request of DeletePetsPetID.
+type DeletePetsPetIDRequest struct {
+	PetID int64 `path:"petId"`
+}
+
+// UploadPhotoRequest is the request of UploadPhoto.
+type UploadPhotoRequest struct {
+	PetID int64 `path:"petId"`
+	Body  struct {
+		Caption string         `form:"caption"`
+		Photo   multipart.File `form:"photo"`
+	} `body:"multipart"`
+}
+
+// PetEvents handles GET /events.
+func PetEvents(e *simpleapi.Endpoint) interface{} {
+	e.WithOperationID("petEvents").
+		WithEventStream(200, Pet{}, "Pet updates").
+		GET()
+
+	return func(ctx *simpleapi.Context) error {
+		return simpleapi.ErrNotImplemented
+	}
+}
+
+// ListPets handles GET /pets: List pets.
+func ListPets(e *simpleapi.Endpoint) interface{} {
+	e.WithOperationID("listPets").
+		WithTag("pets").
+		WithSummary("List pets").
+		WithResponse(200, []Pet{}, "The pets",
+			simpleapi.ResponseHeader("X-Total-Count", 0, "Number of pets")).
+		GET()
+
+	return func(ctx *simpleapi.Context, req ListPetsRequest) error {
+		return simpleapi.ErrNotImplemented
+	}
+}
+
+// CreatePet handles POST /pets.
+func CreatePet(e *simpleapi.Endpoint) interface{} {
+	e.WithOperationID("create-pet").
+		WithTag("pets").
+		WithSecurityRequirement(simpleapi.SecurityRequirement{"api_key": {}, "bearer": {"pets:write"}}).
+		WithResponse(201, CreatePet201Response{}, "Created").
+		POST()
+
+	return func(ctx *simpleapi.Context, req CreatePetRequest) error {
+		return simpleapi.ErrNotImplemented
+	}
+}
+
+// GetPet handles GET /pets/{petId}.
+func GetPet(e *simpleapi.Endpoint) interface{} {
+	e.WithOperationID("getPet").
+		WithResponse(200, Dog{}, "A dog",
+			simpleapi.ResponseVariant("dog")).
+		WithResponse(200, Cat{}, "A cat",
+			simpleapi.ResponseVariant("cat")).
+		WithDiscriminator(200, "kind").
+		WithResponse(404, nil, "Not found").
+		GET()
+
+	return func(ctx *simpleapi.Context, req GetPetRequest) error {
+		return simpleapi.ErrNotImplemented
+	}
+}
+
+// DeletePetsPetID handles DELETE /pets/{petId}.
+func DeletePetsPetID(e *simpleapi.Endpoint) interface{} {
+	e.WithoutSecurity().
+		WithResponse(204, nil, "Deleted").
+		DELETE()
+
+	return func(ctx *simpleapi.Context, req DeletePetsPetIDRequest) error {
+		return simpleapi.ErrNotImplemented
+	}
+}
+
+// UploadPhoto handles PUT /pets/{petId}/photo.
+func UploadPhoto(e *simpleapi.Endpoint) interface{} {
+	e.WithOperationID("uploadPhoto").
+		Deprecated().
+		WithResponse(204, nil, "Uploaded").
+		PUT()
+
+	return func(ctx *simpleapi.Context, req UploadPhotoRequest) error {
+		return simpleapi.ErrNotImplemented
+	}
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"reflect"
 
@@ -16,29 +17,68 @@ type handler struct {
 
 type Handler []handler
 
-func (h handler) Invoke(ctx interface{}, request *http.Request, params router.Params) error {
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// BindError is returned when a request cannot be bound to the parameters of
+// a handler, e.g. when a query parameter is not a number.
+type BindError struct {
+	Err error
+}
+
+func (e *BindError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *BindError) Unwrap() error {
+	return e.Err
+}
+
+// Resolver supplies values for handler parameters that are not bound from the
+// request, such as a websocket connection.
+type Resolver func(t reflect.Type) (reflect.Value, error)
+
+func (h handler) Invoke(ctx interface{}, request *http.Request, params router.Params, resolve Resolver) error {
+	result, err := h.call(ctx, request, params, resolve)
+	if err != nil {
+		return err
+	}
+
+	res := result[0].Interface()
+	if res != nil {
+		return res.(error)
+	}
+
+	return nil
+}
+
+func (h handler) call(ctx interface{}, request *http.Request, params router.Params, resolve Resolver) ([]reflect.Value, error) {
 	fParams := make([]reflect.Value, len(h.ParamTypes))
 
 	for idx, paramType := range h.ParamTypes {
+		if paramType.Kind() != reflect.Struct {
+			if resolve == nil {
+				return nil, fmt.Errorf("no value available for handler parameter of type %v", paramType)
+			}
+			param, err := resolve(paramType)
+			if err != nil {
+				return nil, err
+			}
+			fParams[idx] = param
+			continue
+		}
+
 		param := reflect.New(paramType).Elem()
 
 		err := reflection.PopulateValueFromTypeUsingContext(request, params, paramType, param)
 		if err != nil {
-			return err
+			return nil, &BindError{Err: err}
 		}
 		fParams[idx] = param
 	}
 
-	result := h.Func.Call(append([]reflect.Value{
+	return h.Func.Call(append([]reflect.Value{
 		reflect.ValueOf(ctx),
-	}, fParams...))
-
-	res := result[0].Interface()
-	if res != nil {
-		return res.(error)
-	}
-
-	return nil
+	}, fParams...)), nil
 }
 
 func (h *Handler) HasNext() bool {
@@ -61,33 +101,50 @@ func New(handlers ...interface{}) (*Handler, error) {
 	handlerInstances := make(Handler, len(handlers))
 
 	for idx, handler := range handlers {
-		handlerFunc := reflect.TypeOf(handler)
+		h, err := newHandler(handler)
+		if err != nil {
+			return nil, err
+		}
 
-		if handlerFunc.Kind() != reflect.Func {
-			return nil, errors.New("handler is not a function")
+		if h.Func.Type().NumOut() != 1 || h.Func.Type().Out(0) != errorType {
+			return nil, errors.New("handler must return only an error")
 		}
 
-		funcValue := reflect.ValueOf(handler)
+		handlerInstances[idx] = h
+	}
+
+	return &handlerInstances, nil
+}
 
-		numParams := handlerFunc.NumIn()
+func newHandler(fn interface{}) (handler, error) {
+	handlerFunc := reflect.TypeOf(fn)
 
-		if numParams == 0 {
-			return nil, errors.New("handler must have at least 1 parameter")
-		}
+	if handlerFunc == nil || handlerFunc.Kind() != reflect.Func {
+		return handler{}, errors.New("handler is not a function")
+	}
 
-		paramTypes := make([]reflect.Type, numParams-1)
+	funcValue := reflect.ValueOf(fn)
 
-		for i := 1; i < numParams; i++ {
-			paramTypes[i-1] = handlerFunc.In(i)
+	numParams := handlerFunc.NumIn()
 
-			if paramTypes[i-1].Kind() != reflect.Struct {
-				return nil, errors.New("handler parameter must be a struct")
-			}
-		}
+	if numParams == 0 {
+		return handler{}, errors.New("handler must have at least 1 parameter")
+	}
+
+	paramTypes := make([]reflect.Type, numParams-1)
+
+	for i := 1; i < numParams; i++ {
+		paramTypes[i-1] = handlerFunc.In(i)
 
-		handlerInstances[idx].Func = funcValue
-		handlerInstances[idx].ParamTypes = paramTypes
+		switch paramTypes[i-1].Kind() {
+		case reflect.Struct, reflect.Ptr, reflect.Interface:
+		default:
+			return handler{}, errors.New("handler parameter must be a struct or a resolvable pointer or interface")
+		}
 	}
 
-	return &handlerInstances, nil
+	return handler{
+		Func:       funcValue,
+		ParamTypes: paramTypes,
+	}, nil
 }
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+
+	"github.com/sattvikc/go-simpleapi/router"
+)
+
+// Provider produces a value that can be declared as a handler parameter. Its
+// own parameters are bound and resolved the same way as a handler's.
+type Provider struct {
+	handler
+	Out reflect.Type
+}
+
+func NewProvider(fn interface{}) (*Provider, error) {
+	h, err := newHandler(fn)
+	if err != nil {
+		return nil, err
+	}
+
+	funcType := h.Func.Type()
+	if funcType.NumOut() != 2 || funcType.Out(1) != errorType {
+		return nil, errors.New("provider must return a value and an error")
+	}
+
+	out := funcType.Out(0)
+	if out.Kind() != reflect.Ptr && out.Kind() != reflect.Interface {
+		return nil, errors.New("provider must return a pointer or an interface")
+	}
+
+	return &Provider{
+		handler: h,
+		Out:     out,
+	}, nil
+}
+
+func (p *Provider) Call(ctx interface{}, request *http.Request, params router.Params, resolve Resolver) (reflect.Value, error) {
+	result, err := p.call(ctx, request, params, resolve)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	if res := result[1].Interface(); res != nil {
+		return reflect.Value{}, res.(error)
+	}
+
+	return result[0], nil
+}
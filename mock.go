@@ -0,0 +1,317 @@
+package simpleapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sattvikc/go-simpleapi/openapi"
+)
+
+// ErrNotImplemented is returned by handlers that are not written yet, such
+// as those generated by stubgen. It is answered with 501 Not Implemented,
+// or with a mock response when mocks are enabled.
+var ErrNotImplemented = NewHTTPError(http.StatusNotImplemented, errors.New("not implemented"))
+
+// MockMode selects the endpoints that answer with mock responses.
+type MockMode int
+
+const (
+	// MockUnimplemented mocks endpoints whose builder returns a nil handler
+	// and handlers that return ErrNotImplemented.
+	MockUnimplemented MockMode = iota + 1
+	// MockAll mocks every endpoint except WebSockets. Requests are still
+	// bound and the handlers of builders chained before the last one, such
+	// as authentication, still run.
+	MockAll
+)
+
+// WithMocks answers requests to the endpoints selected by mode with data
+// made up from the declared responses, so that clients can be developed
+// before the handlers exist. The first 2xx response is used, with its
+// example or one synthesized from its type and example tags; a request
+// header like
+//
+//	Prefer: code=404, example=created
+//
+// picks another response and a named example. Requests that cannot be bound,
+// or whose parameters or JSON body do not match the document, are answered
+// with 422 Unprocessable Entity as usual.
+//
+// Mocked endpoints keep request bodies in memory, and mocking is meant for
+// development. The bodies of requests to handlers that return
+// ErrNotImplemented are not kept, so only their parameters are checked.
+func WithMocks(mode MockMode) Option {
+	return func(s *App) {
+		s.mock = mode
+	}
+}
+
+// mockHandler replaces a nil last handler of an endpoint, and every last
+// handler when all endpoints are mocked, with one that binds the same
+// request and returns ErrNotImplemented.
+func (s *App) mockHandler(e *Endpoint) {
+	last := len(e.handlers) - 1
+	if last < 0 || e.websocket {
+		return
+	}
+
+	if e.handlers[last] == nil {
+		e.handlers[last] = func(ctx *Context) error {
+			return ErrNotImplemented
+		}
+		e.mocked = true
+		return
+	}
+
+	fn := reflect.ValueOf(e.handlers[last])
+	if s.mock != MockAll || fn.Kind() != reflect.Func || fn.Type().NumOut() != 1 {
+		return
+	}
+	notImplemented := reflect.New(fn.Type().Out(0)).Elem()
+	if !reflect.TypeOf(ErrNotImplemented).AssignableTo(notImplemented.Type()) {
+		return
+	}
+	notImplemented.Set(reflect.ValueOf(ErrNotImplemented))
+	e.handlers[last] = reflect.MakeFunc(fn.Type(), func([]reflect.Value) []reflect.Value {
+		return []reflect.Value{notImplemented}
+	}).Interface()
+	e.mocked = true
+}
+
+// writeMock answers a request with the declared response selected by its
+// Prefer header, after checking it against the operation. The body is only
+// checked when it was kept, that is when the endpoint is mocked.
+func (s *App) writeMock(e *Endpoint, ctx *Context, body []byte, checkBody bool) error {
+	spec, err := s.cachedSpec()
+	if err != nil {
+		return err
+	}
+	item := spec.doc.Paths[e.path]
+	if item == nil || item.Operation(e.method) == nil {
+		return ErrNotImplemented
+	}
+	op := item.Operation(e.method)
+
+	if problems := validateRequest(spec.doc, op, ctx, body, checkBody); len(problems) > 0 {
+		return NewHTTPError(http.StatusUnprocessableEntity, errors.New(strings.Join(problems, "; ")))
+	}
+
+	prefer := parsePrefer(ctx.Request.Header.Get("Prefer"))
+	code, response := mockResponse(op, prefer["code"])
+	if response == nil {
+		return NewHTTPError(http.StatusNotImplemented, errors.New("no response is declared to mock"))
+	}
+
+	for _, name := range sortedKeys(response.Headers) {
+		header := response.Headers[name]
+		value := header.Example
+		if value == nil {
+			value = spec.doc.Example(header.Schema)
+		}
+		if value != nil {
+			ctx.Response.Header().Set(name, fmt.Sprint(value))
+		}
+	}
+
+	contentType, media := mockContent(response)
+	if media == nil {
+		ctx.Response.WriteHeader(code)
+		return nil
+	}
+	value := mockExample(spec.doc, media, prefer["example"])
+
+	switch {
+	case contentType == "text/event-stream":
+		sse, err := ctx.SSE(code)
+		if err != nil {
+			return err
+		}
+		return sse.Send("", "", value)
+
+	case isJSON(contentType):
+		return ctx.JSON(code, value)
+
+	default:
+		ctx.Response.Header().Set("Content-Type", contentType)
+		ctx.Response.WriteHeader(code)
+		if text, ok := value.(string); ok {
+			_, err = ctx.Response.Write([]byte(text))
+			return err
+		}
+		return json.NewEncoder(ctx.Response).Encode(value)
+	}
+}
+
+// parsePrefer reads the code and example preferences of a Prefer header.
+func parsePrefer(header string) map[string]string {
+	prefer := map[string]string{}
+	for _, part := range strings.Split(header, ",") {
+		if key, value, ok := strings.Cut(strings.TrimSpace(part), "="); ok {
+			prefer[strings.ToLower(key)] = strings.Trim(value, `"`)
+		}
+	}
+	return prefer
+}
+
+// mockResponse returns the response with the preferred code, or else the
+// first 2xx response, or else the first one declared.
+func mockResponse(op *openapi.Operation, preferred string) (int, *openapi.Response) {
+	if response := op.Responses[preferred]; response != nil {
+		code, err := strconv.Atoi(preferred)
+		if err == nil {
+			return code, response
+		}
+	}
+
+	codes := []int{}
+	for key := range op.Responses {
+		if code, err := strconv.Atoi(key); err == nil {
+			codes = append(codes, code)
+		}
+	}
+	if len(codes) == 0 {
+		return 0, nil
+	}
+	sort.Ints(codes)
+
+	chosen := codes[0]
+	for _, code := range codes {
+		if code >= 200 && code < 300 {
+			chosen = code
+			break
+		}
+	}
+	return chosen, op.Responses[strconv.Itoa(chosen)]
+}
+
+// mockContent picks JSON content, then an event stream, then any other.
+func mockContent(response *openapi.Response) (string, *openapi.MediaType) {
+	contentTypes := sortedKeys(response.Content)
+	for _, contentType := range contentTypes {
+		if isJSON(contentType) {
+			return contentType, response.Content[contentType]
+		}
+	}
+	if media, ok := response.Content["text/event-stream"]; ok {
+		return "text/event-stream", media
+	}
+	if len(contentTypes) > 0 {
+		return contentTypes[0], response.Content[contentTypes[0]]
+	}
+	return "", nil
+}
+
+// mockExample returns the named example, the example of the media type, its
+// first named example or one synthesized from its schema.
+func mockExample(doc *openapi.Document, media *openapi.MediaType, name string) interface{} {
+	if example, ok := media.Examples[name]; ok {
+		return example.Value
+	}
+	if media.Example != nil {
+		return media.Example
+	}
+	if names := sortedKeys(media.Examples); len(names) > 0 {
+		return media.Examples[names[0]].Value
+	}
+	return doc.Example(media.Schema)
+}
+
+// validateRequest checks the parameters of a request against an operation,
+// and its JSON body when checkBody is set.
+func validateRequest(doc *openapi.Document, op *openapi.Operation, ctx *Context, body []byte, checkBody bool) []string {
+	problems := []string{}
+
+	for _, parameter := range op.Parameters {
+		var raw string
+		var present bool
+		switch parameter.In {
+		case "path":
+			raw = ctx.params.ByName(parameter.Name)
+			present = raw != ""
+		case "query":
+			values, ok := ctx.Request.URL.Query()[parameter.Name]
+			present = ok && len(values) > 0
+			if present {
+				raw = values[0]
+			}
+		case "header":
+			raw = ctx.Request.Header.Get(parameter.Name)
+			present = raw != ""
+		default:
+			continue
+		}
+
+		name := parameter.In + " parameter " + parameter.Name
+		if !present {
+			if parameter.Required {
+				problems = append(problems, "missing required "+name)
+			}
+			continue
+		}
+		for _, verr := range doc.Validate(parameter.Schema, parameterValue(parameter.Schema, raw)) {
+			problems = append(problems, name+": "+verr.Message)
+		}
+	}
+
+	if !checkBody || op.RequestBody == nil {
+		return problems
+	}
+	if len(body) == 0 {
+		if op.RequestBody.Required {
+			problems = append(problems, "missing required body")
+		}
+		return problems
+	}
+
+	contentType := ctx.Request.Header.Get("Content-Type")
+	if !isJSON(contentType) {
+		return problems
+	}
+	media := op.RequestBody.Content["application/json"]
+	if media == nil || media.Schema == nil {
+		return problems
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return append(problems, "body is not valid JSON: "+err.Error())
+	}
+	for _, verr := range doc.Validate(media.Schema, value) {
+		problems = append(problems, "body "+verr.Error())
+	}
+	return problems
+}
+
+// parameterValue converts a raw parameter to the JSON type of its schema,
+// leaving it a string when it does not parse, so that validation reports it.
+func parameterValue(schema *openapi.Schema, raw string) interface{} {
+	if schema == nil {
+		return raw
+	}
+	switch {
+	case schema.Type.Is("integer"), schema.Type.Is("number"):
+		if v, err := strconv.ParseFloat(raw, 64); err == nil {
+			return v
+		}
+	case schema.Type.Is("boolean"):
+		if v, err := strconv.ParseBool(raw); err == nil {
+			return v
+		}
+	}
+	return raw
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
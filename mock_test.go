@@ -0,0 +1,188 @@
+package simpleapi_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sattvikc/go-simpleapi"
+	"github.com/stretchr/testify/assert"
+)
+
+type NewNovel struct {
+	Body struct {
+		Title string `json:"title"`
+	} `body:"json"`
+}
+
+func mockApp(mode simpleapi.MockMode, called *bool) *simpleapi.App {
+	app := simpleapi.New(simpleapi.WithMocks(mode))
+	app.Endpoint("/novels/{id}", func(e *simpleapi.Endpoint) interface{} {
+		e.WithResponse(200, Novel{}, "The novel").
+			WithResponse(404, nil, "Not found").
+			GET()
+		return func(ctx *simpleapi.Context, req GetNovel) error {
+			return simpleapi.ErrNotImplemented
+		}
+	})
+	app.Endpoint("/novels", func(e *simpleapi.Endpoint) interface{} {
+		e.WithResponse(201, Created{}, "Created",
+			simpleapi.ResponseHeader("Location", "", "URL of the novel"),
+			simpleapi.ResponseExample("first", Created{Status: "OK", ID: 1})).
+			POST()
+		return nil
+	})
+	app.Endpoint("/drafts", func(e *simpleapi.Endpoint) interface{} {
+		e.WithResponse(201, Created{}, "Created").POST()
+		return func(ctx *simpleapi.Context, req NewNovel) error {
+			return simpleapi.ErrNotImplemented
+		}
+	})
+	app.Endpoint("/authors", func(e *simpleapi.Endpoint) interface{} {
+		e.WithResponse(200, []Author{}, "Authors").GET()
+		return func(ctx *simpleapi.Context) error {
+			*called = true
+			return ctx.JSON(200, []Author{{Name: "Ursula K. Le Guin"}, {Name: "Iain M. Banks"}})
+		}
+	})
+	return app
+}
+
+func serve(app *simpleapi.App, method, target, body string, header ...string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	for i := 0; i+1 < len(header); i += 2 {
+		r.Header.Set(header[i], header[i+1])
+	}
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, r)
+	return w
+}
+
+func TestMockUnimplemented(t *testing.T) {
+	called := false
+	app := mockApp(simpleapi.MockUnimplemented, &called)
+
+	w := serve(app, http.MethodGet, "/novels/1?lang=fr", "")
+	assert.Equal(t, 200, w.Code)
+	var novel map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &novel))
+	assert.Equal(t, "string", novel["title"])
+	assert.Equal(t, "novel", novel["kind"])
+	assert.Equal(t, map[string]interface{}{"name": "Ursula K. Le Guin"}, novel["author"])
+
+	w = serve(app, http.MethodGet, "/novels/1?lang=fr", "", "Prefer", "code=404")
+	assert.Equal(t, 404, w.Code)
+	assert.Empty(t, w.Body.String())
+
+	w = serve(app, http.MethodPost, "/novels", `{"title":"The Dispossessed"}`, "Content-Type", "application/json")
+	assert.Equal(t, 201, w.Code)
+	assert.Equal(t, "string", w.Header().Get("Location"))
+	assert.JSONEq(t, `{"status":"OK","id":1}`, w.Body.String())
+
+	w = serve(app, http.MethodGet, "/authors", "")
+	assert.True(t, called)
+	assert.JSONEq(t, `[{"name":"Ursula K. Le Guin"},{"name":"Iain M. Banks"}]`, w.Body.String())
+}
+
+// unreadable is a request body that fails when read.
+type unreadable struct{}
+
+func (unreadable) Read([]byte) (int, error) {
+	return 0, errors.New("unreadable")
+}
+
+func TestMockUnimplementedKeepsOnlyMockedBodies(t *testing.T) {
+	called := false
+	app := mockApp(simpleapi.MockUnimplemented, &called)
+
+	// Endpoints that are not mocked leave the body to their handler.
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/authors", unreadable{}))
+	assert.True(t, called)
+	assert.Equal(t, 200, w.Code)
+
+	// The body of a handler returning ErrNotImplemented is bound, not kept,
+	// so it is not checked against the document.
+	w = serve(app, http.MethodPost, "/drafts", `{}`, "Content-Type", "application/json")
+	assert.Equal(t, 201, w.Code)
+}
+
+func TestMockEventStreamStatus(t *testing.T) {
+	app := simpleapi.New(simpleapi.WithMocks(simpleapi.MockUnimplemented))
+	app.Endpoint("/imports", func(e *simpleapi.Endpoint) interface{} {
+		e.WithEventStream(202, Author{}, "Authors as they are imported").POST()
+		return nil
+	})
+
+	w := serve(app, http.MethodPost, "/imports", "")
+	assert.Equal(t, 202, w.Code)
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	assert.Equal(t, "data: {\"name\":\"Ursula K. Le Guin\"}\n\n", w.Body.String())
+}
+
+func TestMockSkipsRoutesWithoutEndpoint(t *testing.T) {
+	for _, mode := range []simpleapi.MockMode{simpleapi.MockUnimplemented, simpleapi.MockAll} {
+		app := simpleapi.New(simpleapi.WithMocks(mode))
+		app.AddHandler("/raw", http.MethodGet, func(ctx *simpleapi.Context) error {
+			return simpleapi.ErrNotImplemented
+		})
+
+		w := serve(app, http.MethodGet, "/raw", "")
+		assert.Equal(t, 501, w.Code)
+		assert.JSONEq(t, `{"error":"not implemented"}`, w.Body.String())
+	}
+}
+
+func TestMockAll(t *testing.T) {
+	called := false
+	app := mockApp(simpleapi.MockAll, &called)
+
+	w := serve(app, http.MethodGet, "/authors", "")
+	assert.False(t, called)
+	assert.Equal(t, 200, w.Code)
+	assert.JSONEq(t, `[{"name":"Ursula K. Le Guin"}]`, w.Body.String())
+}
+
+func TestMockRejectsBadRequests(t *testing.T) {
+	called := false
+	app := mockApp(simpleapi.MockAll, &called)
+
+	w := serve(app, http.MethodGet, "/novels/abc?lang=fr", "")
+	assert.Equal(t, 422, w.Code)
+
+	w = serve(app, http.MethodGet, "/novels/1?lang=de", "")
+	assert.Equal(t, 422, w.Code)
+	assert.JSONEq(t, `{"error":"query parameter lang: must be one of [\"en\",\"fr\"]"}`, w.Body.String())
+
+	w = serve(app, http.MethodGet, "/novels/1", "")
+	assert.Equal(t, 422, w.Code)
+	assert.JSONEq(t, `{"error":"missing required query parameter lang"}`, w.Body.String())
+
+	w = serve(app, http.MethodPost, "/drafts", `{}`, "Content-Type", "application/json")
+	assert.Equal(t, 422, w.Code)
+	assert.JSONEq(t, `{"error":"body $: missing required property \"title\""}`, w.Body.String())
+
+	w = serve(app, http.MethodPost, "/drafts", `{"title":"Lathe"}`, "Content-Type", "application/json")
+	assert.Equal(t, 201, w.Code)
+}
+
+func TestNotImplementedWithoutMocks(t *testing.T) {
+	app := simpleapi.New()
+	app.Endpoint("/novels", func(e *simpleapi.Endpoint) interface{} {
+		e.WithResponse(201, Created{}, "Created").POST()
+		return nil
+	})
+	app.Endpoint("/novels/{id}", func(e *simpleapi.Endpoint) interface{} {
+		e.GET()
+		return func(ctx *simpleapi.Context, req GetNovel) error {
+			return simpleapi.ErrNotImplemented
+		}
+	})
+
+	assert.Equal(t, 501, serve(app, http.MethodPost, "/novels", "").Code)
+	assert.Equal(t, 501, serve(app, http.MethodGet, "/novels/1", "").Code)
+	assert.Equal(t, 422, serve(app, http.MethodGet, "/novels/abc", "").Code)
+}
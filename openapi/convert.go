@@ -0,0 +1,146 @@
+package openapi
+
+import (
+	"sort"
+	"strings"
+)
+
+// WalkSchemas calls fn for every schema in the document, including nested
+// ones. Children are visited before their parent, so fn may replace the
+// contents of a schema without the walk descending into the result.
+func (d *Document) WalkSchemas(fn func(s *Schema)) {
+	if d.Components != nil {
+		names := make([]string, 0, len(d.Components.Schemas))
+		for name := range d.Components.Schemas {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			walkSchema(d.Components.Schemas[name], fn)
+		}
+	}
+
+	paths := make([]string, 0, len(d.Paths))
+	for path := range d.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		item := d.Paths[path]
+		for _, method := range item.Methods() {
+			op := item.Operation(method)
+			for _, parameter := range op.Parameters {
+				walkSchema(parameter.Schema, fn)
+			}
+			if op.RequestBody != nil {
+				walkContent(op.RequestBody.Content, fn)
+			}
+			for _, code := range sortedKeys(op.Responses) {
+				response := op.Responses[code]
+				for _, name := range sortedKeys(response.Headers) {
+					walkSchema(response.Headers[name].Schema, fn)
+				}
+				walkContent(response.Content, fn)
+			}
+		}
+	}
+}
+
+func walkContent(content map[string]*MediaType, fn func(s *Schema)) {
+	for _, contentType := range sortedKeys(content) {
+		walkSchema(content[contentType].Schema, fn)
+	}
+}
+
+func walkSchema(s *Schema, fn func(s *Schema)) {
+	if s == nil {
+		return
+	}
+
+	for _, name := range sortedKeys(s.Defs) {
+		walkSchema(s.Defs[name], fn)
+	}
+	for _, name := range sortedKeys(s.Properties) {
+		walkSchema(s.Properties[name], fn)
+	}
+	walkSchema(s.AdditionalProperties, fn)
+	walkSchema(s.Items, fn)
+	for _, list := range [][]*Schema{s.AllOf, s.OneOf, s.AnyOf} {
+		for _, child := range list {
+			walkSchema(child, fn)
+		}
+	}
+	walkSchema(s.Not, fn)
+
+	fn(s)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ConvertTo31 rewrites an OpenAPI 3.0 document as OpenAPI 3.1, whose schemas
+// are JSON Schema 2020-12: nullable becomes a "null" type, example becomes
+// examples, single value enums become const, binary and base64 strings are
+// described with contentMediaType and contentEncoding, and $ref may have
+// siblings. Converting a document twice has no further effect.
+func (d *Document) ConvertTo31() {
+	if !strings.HasPrefix(d.OpenAPI, "3.1.") {
+		d.OpenAPI = "3.1.0"
+	}
+	d.WalkSchemas(convertSchemaTo31)
+}
+
+func convertSchemaTo31(s *Schema) {
+	// allOf was only needed to attach keywords to a reference.
+	if len(s.AllOf) == 1 && s.AllOf[0].Ref != "" && s.Ref == "" && s.Type == nil && s.OneOf == nil && s.AnyOf == nil {
+		s.Ref = s.AllOf[0].Ref
+		s.AllOf = nil
+	}
+
+	if s.Nullable {
+		s.Nullable = false
+		switch {
+		case s.Type != nil:
+			if !s.Type.Is("null") {
+				s.Type = append(s.Type, "null")
+			}
+			if s.Enum != nil {
+				s.Enum = append(s.Enum, nil)
+			}
+		case s.Ref != "":
+			s.AnyOf = []*Schema{{Ref: s.Ref}, {Type: Types{"null"}}}
+			s.Ref = ""
+		default:
+			inner := *s
+			*s = Schema{AnyOf: []*Schema{&inner, {Type: Types{"null"}}}}
+		}
+	}
+
+	if s.Example != nil {
+		s.Examples = append(s.Examples, s.Example)
+		s.Example = nil
+	}
+
+	if len(s.Enum) == 1 {
+		s.Const = s.Enum[0]
+		s.Enum = nil
+	}
+
+	if s.Type.Is("string") {
+		switch s.Format {
+		case "binary":
+			s.ContentMediaType = "application/octet-stream"
+			s.Format = ""
+		case "byte":
+			s.ContentEncoding = "base64"
+			s.Format = ""
+		}
+	}
+}
@@ -0,0 +1,427 @@
+package openapi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Change is a difference between two versions of a document.
+type Change struct {
+	// Breaking is set when clients written against the old version may fail
+	// against the new one.
+	Breaking bool
+	// Location is the operation and the part of it that changed, e.g.
+	// "GET /books response 200 application/json $.title".
+	Location string
+	Message  string
+}
+
+func (c Change) String() string {
+	kind := "non-breaking"
+	if c.Breaking {
+		kind = "breaking"
+	}
+	return fmt.Sprintf("[%s] %s: %s", kind, c.Location, c.Message)
+}
+
+// Diff compares two versions of a document and returns the changes from base
+// to revision, in a stable order. Requests and responses are compared in
+// opposite directions: a request schema may accept more but not less, a
+// response schema may promise more but not less.
+func Diff(base, revision *Document) []Change {
+	d := &differ{base: base, revision: revision}
+
+	for _, path := range unionKeys(base.Paths, revision.Paths) {
+		baseItem, revisionItem := base.Paths[path], revision.Paths[path]
+		if revisionItem == nil {
+			d.add(true, path, "path removed")
+			continue
+		}
+		if baseItem == nil {
+			d.add(false, path, "path added")
+			continue
+		}
+
+		for _, method := range []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"} {
+			location := strings.ToUpper(method) + " " + path
+			baseOp, revisionOp := baseItem.Operation(method), revisionItem.Operation(method)
+			switch {
+			case baseOp == nil && revisionOp == nil:
+			case revisionOp == nil:
+				d.add(true, location, "operation removed")
+			case baseOp == nil:
+				d.add(false, location, "operation added")
+			default:
+				d.operation(location, baseOp, revisionOp)
+			}
+		}
+	}
+
+	return d.changes
+}
+
+// HasBreaking reports whether any of the changes is breaking.
+func HasBreaking(changes []Change) bool {
+	for _, c := range changes {
+		if c.Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+type direction int
+
+const (
+	request direction = iota
+	response
+)
+
+type differ struct {
+	base, revision *Document
+	changes        []Change
+	// visiting holds the pairs of references being compared, so that
+	// recursive schemas are compared once.
+	visiting map[[2]string]bool
+}
+
+func (d *differ) add(breaking bool, location, format string, args ...interface{}) {
+	d.changes = append(d.changes, Change{Breaking: breaking, Location: location, Message: fmt.Sprintf(format, args...)})
+}
+
+func (d *differ) operation(location string, base, revision *Operation) {
+	if !base.Deprecated && revision.Deprecated {
+		d.add(false, location, "operation deprecated")
+	}
+
+	baseParams, revisionParams := parameterMap(base.Parameters), parameterMap(revision.Parameters)
+	for _, key := range unionKeys(baseParams, revisionParams) {
+		b, r := baseParams[key], revisionParams[key]
+		paramLocation := location + " " + key
+		switch {
+		case r == nil:
+			d.add(false, paramLocation, "parameter removed")
+		case b == nil && r.Required:
+			d.add(true, paramLocation, "required parameter added")
+		case b == nil:
+			d.add(false, paramLocation, "optional parameter added")
+		default:
+			if !b.Required && r.Required {
+				d.add(true, paramLocation, "parameter became required")
+			} else if b.Required && !r.Required {
+				d.add(false, paramLocation, "parameter became optional")
+			}
+			d.schema(paramLocation, b.Schema, r.Schema, request)
+		}
+	}
+
+	d.requestBody(location+" request body", base.RequestBody, revision.RequestBody)
+
+	for _, code := range unionKeys(base.Responses, revision.Responses) {
+		b, r := base.Responses[code], revision.Responses[code]
+		responseLocation := location + " response " + code
+		switch {
+		case r == nil:
+			d.add(true, responseLocation, "response removed")
+		case b == nil:
+			d.add(false, responseLocation, "response added")
+		default:
+			d.content(responseLocation, b.Content, r.Content, response)
+		}
+	}
+}
+
+func (d *differ) requestBody(location string, base, revision *RequestBody) {
+	switch {
+	case base == nil && revision == nil:
+	case revision == nil:
+		d.add(false, location, "request body removed")
+	case base == nil && revision.Required:
+		d.add(true, location, "required request body added")
+	case base == nil:
+		d.add(false, location, "optional request body added")
+	default:
+		if !base.Required && revision.Required {
+			d.add(true, location, "request body became required")
+		}
+		d.content(location, base.Content, revision.Content, request)
+	}
+}
+
+func (d *differ) content(location string, base, revision map[string]*MediaType, dir direction) {
+	for _, contentType := range unionKeys(base, revision) {
+		b, r := base[contentType], revision[contentType]
+		mediaLocation := location + " " + contentType
+		switch {
+		case r == nil:
+			d.add(true, mediaLocation, "content type removed")
+		case b == nil:
+			d.add(false, mediaLocation, "content type added")
+		default:
+			d.schema(mediaLocation+" $", b.Schema, r.Schema, dir)
+		}
+	}
+}
+
+func (d *differ) resolve(doc *Document, s *Schema) *Schema {
+	for depth := 0; s != nil && s.Ref != "" && depth < maxDepth; depth++ {
+		name := strings.TrimPrefix(s.Ref, "#/components/schemas/")
+		if doc.Components == nil || doc.Components.Schemas[name] == nil {
+			return nil
+		}
+		s = doc.Components.Schemas[name]
+	}
+	return s
+}
+
+func (d *differ) schema(location string, base, revision *Schema, dir direction) {
+	if base != nil && revision != nil && base.Ref != "" && revision.Ref != "" {
+		key := [2]string{base.Ref, revision.Ref}
+		if d.visiting[key] {
+			return
+		}
+		if d.visiting == nil {
+			d.visiting = map[[2]string]bool{}
+		}
+		d.visiting[key] = true
+		defer delete(d.visiting, key)
+	}
+
+	b, r := d.resolve(d.base, base), d.resolve(d.revision, revision)
+	if b == nil || r == nil {
+		return
+	}
+
+	d.types(location, b, r, dir)
+	d.enum(location, b, r, dir)
+	d.bounds(location, b, r, dir)
+	d.alternatives(location, b, r, dir)
+
+	for _, name := range unionKeys(b.Properties, r.Properties) {
+		bp, rp := b.Properties[name], r.Properties[name]
+		propertyLocation := location + "." + name
+		switch {
+		case rp == nil && dir == response:
+			d.add(true, propertyLocation, "property removed")
+		case rp == nil:
+			d.add(false, propertyLocation, "property removed")
+		case bp == nil && dir == request && contains(r.Required, name):
+			d.add(true, propertyLocation, "required property added")
+		case bp == nil:
+			d.add(false, propertyLocation, "property added")
+		default:
+			if dir == request && !contains(b.Required, name) && contains(r.Required, name) {
+				d.add(true, propertyLocation, "property became required")
+			}
+			if dir == response && contains(b.Required, name) && !contains(r.Required, name) {
+				d.add(true, propertyLocation, "property became optional")
+			}
+			d.schema(propertyLocation, bp, rp, dir)
+		}
+	}
+
+	if b.Items != nil && r.Items != nil {
+		d.schema(location+"[]", b.Items, r.Items, dir)
+	}
+	if b.AdditionalProperties != nil && r.AdditionalProperties != nil {
+		d.schema(location+"{}", b.AdditionalProperties, r.AdditionalProperties, dir)
+	}
+	if len(b.AllOf) == len(r.AllOf) {
+		for i := range b.AllOf {
+			d.schema(location, b.AllOf[i], r.AllOf[i], dir)
+		}
+	}
+}
+
+// typeSet returns the types a schema accepts, with null for nullable
+// schemas. An empty set accepts anything.
+func typeSet(s *Schema) []string {
+	types := append([]string{}, s.Type...)
+	if s.Nullable && len(types) > 0 && !s.Type.Is("null") {
+		types = append(types, "null")
+	}
+	return types
+}
+
+func acceptsType(types []string, typ string) bool {
+	if len(types) == 0 {
+		return true
+	}
+	for _, t := range types {
+		if t == typ || t == "number" && typ == "integer" {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *differ) types(location string, b, r *Schema, dir direction) {
+	bTypes, rTypes := typeSet(b), typeSet(r)
+
+	// Requests must still accept what they accepted, responses must not
+	// send anything new.
+	from, to := bTypes, rTypes
+	message := "no longer accepts %s"
+	if dir == response {
+		from, to = rTypes, bTypes
+		message = "may now be %s"
+	}
+
+	if len(from) == 0 && len(to) > 0 {
+		if dir == request {
+			d.add(true, location, "type restricted to %s", strings.Join(rTypes, " or "))
+		} else {
+			d.add(true, location, "type no longer restricted")
+		}
+		return
+	}
+	for _, typ := range from {
+		if !acceptsType(to, typ) {
+			d.add(true, location, message, typ)
+		}
+	}
+}
+
+func (d *differ) enum(location string, b, r *Schema, dir direction) {
+	bValues, rValues := enumValues(b), enumValues(r)
+	if bValues == nil && rValues == nil {
+		return
+	}
+
+	if bValues == nil {
+		d.add(dir == request, location, "values restricted to %s", jsonString(rValues))
+		return
+	}
+	if rValues == nil {
+		d.add(dir == response, location, "values no longer restricted")
+		return
+	}
+
+	for _, v := range bValues {
+		if !containsJSON(rValues, v) {
+			d.add(dir == request, location, "value %s removed", jsonString(v))
+		}
+	}
+	for _, v := range rValues {
+		if !containsJSON(bValues, v) {
+			d.add(dir == response, location, "value %s added", jsonString(v))
+		}
+	}
+}
+
+// enumValues returns the allowed values of a schema from enum or const, or
+// nil when any value is allowed.
+func enumValues(s *Schema) []interface{} {
+	if s.Enum != nil {
+		return s.Enum
+	}
+	if s.Const != nil {
+		return []interface{}{s.Const}
+	}
+	return nil
+}
+
+func (d *differ) bounds(location string, b, r *Schema, dir direction) {
+	type bound struct {
+		name      string
+		base, rev *float64
+		lower     bool
+	}
+	bounds := []bound{
+		{"minimum", b.Minimum, r.Minimum, true},
+		{"maximum", b.Maximum, r.Maximum, false},
+		{"minLength", intFloat(b.MinLength), intFloat(r.MinLength), true},
+		{"maxLength", intFloat(b.MaxLength), intFloat(r.MaxLength), false},
+		{"minItems", intFloat(b.MinItems), intFloat(r.MinItems), true},
+		{"maxItems", intFloat(b.MaxItems), intFloat(r.MaxItems), false},
+	}
+
+	for _, bd := range bounds {
+		if bd.base == nil && bd.rev == nil || bd.base != nil && bd.rev != nil && *bd.base == *bd.rev {
+			continue
+		}
+
+		// A bound narrows when it is added, or moves inwards.
+		narrowed := bd.base == nil ||
+			bd.rev != nil && (bd.lower && *bd.rev > *bd.base || !bd.lower && *bd.rev < *bd.base)
+		breaking := narrowed == (dir == request)
+
+		switch {
+		case bd.rev == nil:
+			d.add(breaking, location, "%s %v removed", bd.name, *bd.base)
+		case bd.base == nil:
+			d.add(breaking, location, "%s %v added", bd.name, *bd.rev)
+		default:
+			d.add(breaking, location, "%s changed from %v to %v", bd.name, *bd.base, *bd.rev)
+		}
+	}
+}
+
+func intFloat(v *int) *float64 {
+	if v == nil {
+		return nil
+	}
+	f := float64(*v)
+	return &f
+}
+
+// alternatives compares oneOf and anyOf by their references: requests may
+// not drop alternatives, responses may not add them.
+func (d *differ) alternatives(location string, b, r *Schema, dir direction) {
+	for _, pair := range [][2][]*Schema{{b.OneOf, r.OneOf}, {b.AnyOf, r.AnyOf}} {
+		bRefs, rRefs := refs(pair[0]), refs(pair[1])
+		for _, ref := range bRefs {
+			if !contains(rRefs, ref) {
+				d.add(dir == request, location, "alternative %s removed", ref)
+			}
+		}
+		for _, ref := range rRefs {
+			if !contains(bRefs, ref) {
+				d.add(dir == response, location, "alternative %s added", ref)
+			}
+		}
+	}
+}
+
+func refs(schemas []*Schema) []string {
+	result := []string{}
+	for _, s := range schemas {
+		if s.Ref != "" {
+			result = append(result, s.Ref)
+		}
+	}
+	return result
+}
+
+func parameterMap(parameters []*Parameter) map[string]*Parameter {
+	result := map[string]*Parameter{}
+	for _, p := range parameters {
+		name := p.Name
+		if p.In == "header" {
+			name = strings.ToLower(name)
+		}
+		result[p.In+" "+name] = p
+	}
+	return result
+}
+
+func unionKeys[V any](a, b map[string]V) []string {
+	keys := sortedKeys(a)
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package openapi_test
+
+import (
+	"testing"
+
+	"github.com/sattvikc/go-simpleapi/openapi"
+	"github.com/stretchr/testify/assert"
+)
+
+const baseSpec = `
+openapi: 3.0.0
+info: {title: Books, version: "1"}
+paths:
+  /books:
+    get:
+      parameters:
+        - {name: sort, in: query, required: false, schema: {type: string, enum: [asc, desc]}}
+        - {name: limit, in: query, required: false, schema: {type: integer, maximum: 100}}
+      responses:
+        200:
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: array
+                items: {$ref: '#/components/schemas/Book'}
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/Book'}
+      responses:
+        201: {description: Created}
+  /authors:
+    get:
+      responses:
+        200: {description: OK}
+components:
+  schemas:
+    Book:
+      type: object
+      required: [title, isbn]
+      properties:
+        title: {type: string}
+        isbn: {type: string}
+        pages: {type: integer}
+        related: {type: array, items: {$ref: '#/components/schemas/Book'}}
+`
+
+const revisionSpec = `{
+  "openapi": "3.0.0",
+  "info": {"title": "Books", "version": "2"},
+  "paths": {
+    "/books": {
+      "get": {
+        "parameters": [
+          {"name": "sort", "in": "query", "required": true, "schema": {"type": "string", "enum": ["asc"]}},
+          {"name": "limit", "in": "query", "required": false, "schema": {"type": "integer", "maximum": 500}},
+          {"name": "X-Trace", "in": "header", "required": false, "schema": {"type": "string"}}
+        ],
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {"type": "array", "items": {"$ref": "#/components/schemas/Book"}}
+              }
+            }
+          }
+        }
+      },
+      "post": {
+        "requestBody": {
+          "content": {
+            "application/json": {"schema": {"$ref": "#/components/schemas/Book"}}
+          }
+        },
+        "responses": {"201": {"description": "Created"}, "409": {"description": "Exists"}}
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "Book": {
+        "type": "object",
+        "required": ["title", "author"],
+        "properties": {
+          "title": {"type": "string"},
+          "pages": {"type": "number"},
+          "author": {"type": "string"},
+          "related": {"type": "array", "items": {"$ref": "#/components/schemas/Book"}}
+        }
+      }
+    }
+  }
+}`
+
+func TestDiff(t *testing.T) {
+	base, err := openapi.Load([]byte(baseSpec))
+	assert.NoError(t, err)
+	revision, err := openapi.Load([]byte(revisionSpec))
+	assert.NoError(t, err)
+
+	changes := []string{}
+	for _, change := range openapi.Diff(base, revision) {
+		changes = append(changes, change.String())
+	}
+
+	assert.Equal(t, []string{
+		"[breaking] /authors: path removed",
+		"[non-breaking] GET /books header x-trace: optional parameter added",
+		"[non-breaking] GET /books query limit: maximum changed from 100 to 500",
+		"[breaking] GET /books query sort: parameter became required",
+		`[breaking] GET /books query sort: value "desc" removed`,
+		"[non-breaking] GET /books response 200 application/json $[].author: property added",
+		"[breaking] GET /books response 200 application/json $[].isbn: property removed",
+		"[breaking] GET /books response 200 application/json $[].pages: may now be number",
+		"[breaking] POST /books request body application/json $.author: required property added",
+		"[non-breaking] POST /books request body application/json $.isbn: property removed",
+		"[non-breaking] POST /books response 409: response added",
+	}, changes)
+	assert.True(t, openapi.HasBreaking(openapi.Diff(base, revision)))
+	assert.Empty(t, openapi.Diff(base, base))
+}
@@ -0,0 +1,213 @@
+package openapi
+
+import (
+	"math"
+	"strings"
+)
+
+// Example returns an example value for schema, as decoded by encoding/json.
+// The example, default, const or first enum value of a schema is used when
+// it has one; otherwise a value is made up from the type and format, with
+// every property of objects and one item in arrays. Alternatives of a oneOf
+// or anyOf are represented by the first, with its discriminator value set.
+func (d *Document) Example(schema *Schema) interface{} {
+	e := &exampler{doc: d, visiting: map[string]bool{}}
+	return e.example(schema)
+}
+
+type exampler struct {
+	doc *Document
+	// visiting holds the references being expanded, to stop at cycles.
+	visiting map[string]bool
+}
+
+func (e *exampler) example(s *Schema) interface{} {
+	if s == nil {
+		return nil
+	}
+
+	switch {
+	case s.Example != nil:
+		return s.Example
+	case len(s.Examples) > 0:
+		return s.Examples[0]
+	case s.Default != nil:
+		return s.Default
+	case s.Const != nil:
+		return s.Const
+	case len(s.Enum) > 0 && s.Enum[0] != nil:
+		return s.Enum[0]
+	}
+
+	if s.Ref != "" {
+		name := strings.TrimPrefix(s.Ref, "#/components/schemas/")
+		if e.visiting[name] || e.doc.Components == nil || e.doc.Components.Schemas[name] == nil {
+			return nil
+		}
+		e.visiting[name] = true
+		defer delete(e.visiting, name)
+		return e.example(e.doc.Components.Schemas[name])
+	}
+
+	if len(s.AllOf) > 0 {
+		return e.allOf(s.AllOf)
+	}
+	for _, alternatives := range [][]*Schema{s.OneOf, s.AnyOf} {
+		if len(alternatives) > 0 {
+			return e.alternative(s.Discriminator, alternatives)
+		}
+	}
+
+	typ := ""
+	for _, t := range s.Type {
+		if t != "null" {
+			typ = t
+			break
+		}
+	}
+	if typ == "" && s.Properties != nil {
+		typ = "object"
+	}
+
+	switch typ {
+	case "string":
+		return stringExample(s)
+	case "integer":
+		return int64(numberExample(s))
+	case "number":
+		return numberExample(s)
+	case "boolean":
+		return true
+	case "array":
+		items := []interface{}{}
+		count := 1
+		if s.MinItems != nil && *s.MinItems > count {
+			count = *s.MinItems
+		}
+		if s.MaxItems != nil && *s.MaxItems < count {
+			count = *s.MaxItems
+		}
+		for i := 0; i < count; i++ {
+			item := e.example(s.Items)
+			if item == nil {
+				break
+			}
+			items = append(items, item)
+		}
+		return items
+	case "object":
+		object := map[string]interface{}{}
+		for _, name := range sortedKeys(s.Properties) {
+			if value := e.example(s.Properties[name]); value != nil {
+				object[name] = value
+			}
+		}
+		if len(s.Properties) == 0 && s.AdditionalProperties != nil {
+			if value := e.example(s.AdditionalProperties); value != nil {
+				object["key"] = value
+			}
+		}
+		return object
+	}
+	return nil
+}
+
+// allOf merges the examples of objects, and otherwise uses the first.
+func (e *exampler) allOf(schemas []*Schema) interface{} {
+	var merged map[string]interface{}
+	for _, schema := range schemas {
+		value := e.example(schema)
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			if merged == nil {
+				return value
+			}
+			continue
+		}
+		if merged == nil {
+			merged = map[string]interface{}{}
+		}
+		for key, v := range object {
+			merged[key] = v
+		}
+	}
+	if merged == nil {
+		return nil
+	}
+	return merged
+}
+
+func (e *exampler) alternative(discriminator *Discriminator, alternatives []*Schema) interface{} {
+	for _, alternative := range alternatives {
+		if len(alternative.Type) == 1 && alternative.Type[0] == "null" {
+			continue
+		}
+		value := e.example(alternative)
+		if value == nil {
+			continue
+		}
+
+		object, ok := value.(map[string]interface{})
+		if discriminator == nil || !ok || alternative.Ref == "" {
+			return value
+		}
+		tag := strings.TrimPrefix(alternative.Ref, "#/components/schemas/")
+		for key, ref := range discriminator.Mapping {
+			if ref == alternative.Ref {
+				tag = key
+				break
+			}
+		}
+		object[discriminator.PropertyName] = tag
+		return object
+	}
+	return nil
+}
+
+func stringExample(s *Schema) string {
+	var value string
+	switch s.Format {
+	case "date-time":
+		value = "2006-01-02T15:04:05Z"
+	case "date":
+		value = "2006-01-02"
+	case "time":
+		value = "15:04:05Z"
+	case "email":
+		value = "user@example.com"
+	case "uuid":
+		value = "3fa85f64-5717-4562-b3fc-2c963f66afa6"
+	case "uri", "url":
+		value = "https://example.com"
+	case "hostname":
+		value = "example.com"
+	case "ipv4":
+		value = "192.0.2.1"
+	case "ipv6":
+		value = "2001:db8::1"
+	case "byte":
+		value = "c3RyaW5n"
+	case "binary":
+		value = ""
+	default:
+		value = "string"
+	}
+
+	if s.MaxLength != nil && len(value) > *s.MaxLength {
+		value = value[:*s.MaxLength]
+	}
+	if s.MinLength != nil && len(value) < *s.MinLength {
+		value += strings.Repeat("x", *s.MinLength-len(value))
+	}
+	return value
+}
+
+func numberExample(s *Schema) float64 {
+	switch {
+	case s.Minimum != nil:
+		return math.Ceil(*s.Minimum)
+	case s.Maximum != nil && *s.Maximum < 0:
+		return math.Floor(*s.Maximum)
+	}
+	return 0
+}
@@ -0,0 +1,40 @@
+package openapi_test
+
+import (
+	"testing"
+
+	"github.com/sattvikc/go-simpleapi/openapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExample(t *testing.T) {
+	doc := &openapi.Document{Components: &openapi.Components{Schemas: map[string]*openapi.Schema{
+		"Node": {
+			Type: openapi.Types{"object"},
+			Properties: map[string]*openapi.Schema{
+				"name":     {Type: openapi.Types{"string"}, Example: "root"},
+				"created":  {Type: openapi.Types{"string"}, Format: "date-time"},
+				"size":     {Type: openapi.Types{"integer"}, Minimum: openapi.Float(1)},
+				"kind":     {Type: openapi.Types{"string"}, Enum: []interface{}{"dir", "file"}},
+				"children": {Type: openapi.Types{"array"}, Items: openapi.RefTo("Node")},
+			},
+		},
+		"Dir":  {AllOf: []*openapi.Schema{openapi.RefTo("Node")}},
+		"File": {Type: openapi.Types{"object"}, Properties: map[string]*openapi.Schema{"type": {Type: openapi.Types{"string"}}}},
+	}}}
+
+	assert.Equal(t, map[string]interface{}{
+		"name":     "root",
+		"created":  "2006-01-02T15:04:05Z",
+		"size":     int64(1),
+		"kind":     "dir",
+		"children": []interface{}{},
+	}, doc.Example(openapi.RefTo("Node")))
+
+	assert.Equal(t, map[string]interface{}{"type": "file"}, doc.Example(&openapi.Schema{
+		OneOf:         []*openapi.Schema{openapi.RefTo("File"), openapi.RefTo("Dir")},
+		Discriminator: &openapi.Discriminator{PropertyName: "type", Mapping: map[string]string{"file": "#/components/schemas/File"}},
+	}))
+
+	assert.Equal(t, "stringxx", doc.Example(&openapi.Schema{Type: openapi.Types{"string"}, MinLength: openapi.Int(8)}))
+}
@@ -0,0 +1,203 @@
+// Package openapi is a typed model of an OpenAPI 3 document.
+package openapi
+
+// Document is the root object of an OpenAPI document.
+type Document struct {
+	OpenAPI      string                `json:"openapi"`
+	Info         Info                  `json:"info"`
+	Servers      []Server              `json:"servers,omitempty"`
+	Paths        map[string]*PathItem  `json:"paths"`
+	Components   *Components           `json:"components,omitempty"`
+	Security     []SecurityRequirement `json:"security,omitempty"`
+	Tags         []Tag                 `json:"tags,omitempty"`
+	ExternalDocs *ExternalDocs         `json:"externalDocs,omitempty"`
+}
+
+type Info struct {
+	Title          string   `json:"title"`
+	Description    string   `json:"description,omitempty"`
+	TermsOfService string   `json:"termsOfService,omitempty"`
+	Contact        *Contact `json:"contact,omitempty"`
+	License        *License `json:"license,omitempty"`
+	Version        string   `json:"version"`
+}
+
+type Contact struct {
+	Name  string `json:"name,omitempty"`
+	URL   string `json:"url,omitempty"`
+	Email string `json:"email,omitempty"`
+}
+
+type License struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+type Server struct {
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+type Tag struct {
+	Name         string        `json:"name"`
+	Description  string        `json:"description,omitempty"`
+	ExternalDocs *ExternalDocs `json:"externalDocs,omitempty"`
+}
+
+type ExternalDocs struct {
+	Description string `json:"description,omitempty"`
+	URL         string `json:"url"`
+}
+
+type PathItem struct {
+	Get     *Operation `json:"get,omitempty"`
+	Put     *Operation `json:"put,omitempty"`
+	Post    *Operation `json:"post,omitempty"`
+	Delete  *Operation `json:"delete,omitempty"`
+	Options *Operation `json:"options,omitempty"`
+	Head    *Operation `json:"head,omitempty"`
+	Patch   *Operation `json:"patch,omitempty"`
+	Trace   *Operation `json:"trace,omitempty"`
+}
+
+// Operation returns the operation for an HTTP method, in any case.
+func (p *PathItem) Operation(method string) *Operation {
+	if field := p.field(method); field != nil {
+		return *field
+	}
+	return nil
+}
+
+// SetOperation sets the operation for an HTTP method, in any case.
+func (p *PathItem) SetOperation(method string, op *Operation) {
+	if field := p.field(method); field != nil {
+		*field = op
+	}
+}
+
+// Methods lists the lower case methods that have an operation, in the order
+// they appear in the document.
+func (p *PathItem) Methods() []string {
+	methods := []string{}
+	for _, method := range []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"} {
+		if p.Operation(method) != nil {
+			methods = append(methods, method)
+		}
+	}
+	return methods
+}
+
+func (p *PathItem) field(method string) **Operation {
+	switch method {
+	case "get", "GET":
+		return &p.Get
+	case "put", "PUT":
+		return &p.Put
+	case "post", "POST":
+		return &p.Post
+	case "delete", "DELETE":
+		return &p.Delete
+	case "options", "OPTIONS":
+		return &p.Options
+	case "head", "HEAD":
+		return &p.Head
+	case "patch", "PATCH":
+		return &p.Patch
+	case "trace", "TRACE":
+		return &p.Trace
+	}
+	return nil
+}
+
+type Operation struct {
+	Tags         []string              `json:"tags"`
+	Summary      string                `json:"summary,omitempty"`
+	Description  string                `json:"description,omitempty"`
+	ExternalDocs *ExternalDocs         `json:"externalDocs,omitempty"`
+	OperationID  string                `json:"operationId,omitempty"`
+	Parameters   []*Parameter          `json:"parameters"`
+	RequestBody  *RequestBody          `json:"requestBody,omitempty"`
+	Responses    map[string]*Response  `json:"responses"`
+	Deprecated   bool                  `json:"deprecated,omitempty"`
+	Security     *SecurityRequirements `json:"security,omitempty"`
+}
+
+type Parameter struct {
+	Name        string      `json:"name"`
+	In          string      `json:"in"`
+	Description string      `json:"description,omitempty"`
+	Required    bool        `json:"required"`
+	Deprecated  bool        `json:"deprecated,omitempty"`
+	Schema      *Schema     `json:"schema,omitempty"`
+	Example     interface{} `json:"example,omitempty"`
+}
+
+type RequestBody struct {
+	Description string                `json:"description,omitempty"`
+	Content     map[string]*MediaType `json:"content"`
+	Required    bool                  `json:"required,omitempty"`
+}
+
+type MediaType struct {
+	Schema   *Schema             `json:"schema,omitempty"`
+	Example  interface{}         `json:"example,omitempty"`
+	Examples map[string]*Example `json:"examples,omitempty"`
+}
+
+type Example struct {
+	Summary     string      `json:"summary,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Value       interface{} `json:"value,omitempty"`
+}
+
+type Response struct {
+	Description string                `json:"description"`
+	Headers     map[string]*Header    `json:"headers,omitempty"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+type Header struct {
+	Description string      `json:"description,omitempty"`
+	Required    bool        `json:"required,omitempty"`
+	Deprecated  bool        `json:"deprecated,omitempty"`
+	Schema      *Schema     `json:"schema,omitempty"`
+	Example     interface{} `json:"example,omitempty"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas,omitempty"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type             string      `json:"type"`
+	Description      string      `json:"description,omitempty"`
+	Name             string      `json:"name,omitempty"`
+	In               string      `json:"in,omitempty"`
+	Scheme           string      `json:"scheme,omitempty"`
+	BearerFormat     string      `json:"bearerFormat,omitempty"`
+	Flows            *OAuthFlows `json:"flows,omitempty"`
+	OpenIDConnectURL string      `json:"openIdConnectUrl,omitempty"`
+}
+
+type OAuthFlows struct {
+	Implicit          *OAuthFlow `json:"implicit,omitempty"`
+	Password          *OAuthFlow `json:"password,omitempty"`
+	ClientCredentials *OAuthFlow `json:"clientCredentials,omitempty"`
+	AuthorizationCode *OAuthFlow `json:"authorizationCode,omitempty"`
+}
+
+type OAuthFlow struct {
+	AuthorizationURL string            `json:"authorizationUrl,omitempty"`
+	TokenURL         string            `json:"tokenUrl,omitempty"`
+	RefreshURL       string            `json:"refreshUrl,omitempty"`
+	Scopes           map[string]string `json:"scopes"`
+}
+
+// SecurityRequirements are alternatives, any one of which grants access. An
+// empty list makes an operation public.
+type SecurityRequirements []SecurityRequirement
+
+// SecurityRequirement maps security scheme names to the scopes required. All
+// schemes in one requirement must be satisfied together.
+type SecurityRequirement map[string][]string
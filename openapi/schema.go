@@ -0,0 +1,187 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Schema is a JSON schema as used by OpenAPI. Only the keywords that the
+// generator emits, or that are commonly set by hand, are modelled; others,
+// and x- extensions, are kept in Extensions.
+type Schema struct {
+	Ref  string             `json:"$ref,omitempty"`
+	Defs map[string]*Schema `json:"$defs,omitempty"`
+
+	Type        Types  `json:"type,omitempty"`
+	Format      string `json:"format,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Nullable    bool   `json:"nullable,omitempty"`
+
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	UniqueItems          bool               `json:"uniqueItems,omitempty"`
+
+	AllOf         []*Schema      `json:"allOf,omitempty"`
+	OneOf         []*Schema      `json:"oneOf,omitempty"`
+	AnyOf         []*Schema      `json:"anyOf,omitempty"`
+	Not           *Schema        `json:"not,omitempty"`
+	Discriminator *Discriminator `json:"discriminator,omitempty"`
+
+	Enum      []interface{} `json:"enum,omitempty"`
+	Const     interface{}   `json:"const,omitempty"`
+	Default   interface{}   `json:"default,omitempty"`
+	Example   interface{}   `json:"example,omitempty"`
+	Examples  []interface{} `json:"examples,omitempty"`
+	Minimum   *float64      `json:"minimum,omitempty"`
+	Maximum   *float64      `json:"maximum,omitempty"`
+	MinLength *int          `json:"minLength,omitempty"`
+	MaxLength *int          `json:"maxLength,omitempty"`
+	Pattern   string        `json:"pattern,omitempty"`
+
+	ContentMediaType string `json:"contentMediaType,omitempty"`
+	ContentEncoding  string `json:"contentEncoding,omitempty"`
+
+	ReadOnly   bool `json:"readOnly,omitempty"`
+	WriteOnly  bool `json:"writeOnly,omitempty"`
+	Deprecated bool `json:"deprecated,omitempty"`
+
+	// Extensions holds the keywords that are not modelled above, such as
+	// x-go-type or multipleOf, as decoded by encoding/json. They are written
+	// after the modelled ones and never replace them.
+	Extensions map[string]interface{} `json:"-"`
+}
+
+// schemaFields has the fields of Schema without its methods.
+type schemaFields Schema
+
+// schemaKeywords holds the keywords modelled by the fields of Schema.
+var schemaKeywords = func() map[string]bool {
+	keywords := map[string]bool{}
+	t := reflect.TypeOf(Schema{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "-" {
+			keywords[name] = true
+		}
+	}
+	return keywords
+}()
+
+func (s Schema) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(schemaFields(s))
+	if err != nil || len(s.Extensions) == 0 {
+		return data, err
+	}
+
+	names := make([]string, 0, len(s.Extensions))
+	for name := range s.Extensions {
+		if !schemaKeywords[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	buf.Write(data[:len(data)-1])
+	for _, name := range names {
+		value, err := json.Marshal(s.Extensions[name])
+		if err != nil {
+			return nil, err
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(name)
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, (*schemaFields)(s)); err != nil {
+		return err
+	}
+
+	var keywords map[string]json.RawMessage
+	if err := json.Unmarshal(data, &keywords); err != nil {
+		return err
+	}
+	for name, raw := range keywords {
+		if schemaKeywords[name] {
+			continue
+		}
+		var value interface{}
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return err
+		}
+		if s.Extensions == nil {
+			s.Extensions = map[string]interface{}{}
+		}
+		s.Extensions[name] = value
+	}
+	return nil
+}
+
+// Discriminator names the property that tells the alternatives of a oneOf
+// or anyOf apart. Mapping maps its values to schema references; without it
+// the value is the name of the component schema.
+type Discriminator struct {
+	PropertyName string            `json:"propertyName"`
+	Mapping      map[string]string `json:"mapping,omitempty"`
+}
+
+// RefTo returns a schema referencing the named component schema.
+func RefTo(name string) *Schema {
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// Int returns a pointer to v, for the optional integer keywords.
+func Int(v int) *int {
+	return &v
+}
+
+// Float returns a pointer to v, for the optional number keywords.
+func Float(v float64) *float64 {
+	return &v
+}
+
+// Types is the type keyword of a schema. It holds a single type in OpenAPI
+// 3.0, and is encoded as a plain string whenever it has only one.
+type Types []string
+
+// Is reports whether typ is one of the types.
+func (t Types) Is(typ string) bool {
+	for _, v := range t {
+		if v == typ {
+			return true
+		}
+	}
+	return false
+}
+
+func (t Types) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+func (t *Types) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = Types{single}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(t))
+}
@@ -0,0 +1,56 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/sattvikc/go-simpleapi/openapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaExtensions(t *testing.T) {
+	data := `{"type":"integer","multipleOf":5,"x-go-type":"Amount","x-tags":["money"]}`
+
+	schema := &openapi.Schema{}
+	assert.NoError(t, json.Unmarshal([]byte(data), schema))
+	assert.Equal(t, openapi.Types{"integer"}, schema.Type)
+	assert.Equal(t, map[string]interface{}{
+		"multipleOf": float64(5),
+		"x-go-type":  "Amount",
+		"x-tags":     []interface{}{"money"},
+	}, schema.Extensions)
+
+	out, err := json.Marshal(schema)
+	assert.NoError(t, err)
+	assert.Equal(t, data, string(out))
+
+	// Modelled keywords win over extensions of the same name.
+	out, err = json.Marshal(openapi.Schema{Extensions: map[string]interface{}{"type": "string", "x-a": 1}})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"x-a":1}`, string(out))
+}
+
+func TestLoadKeepsSchemaExtensions(t *testing.T) {
+	doc, err := openapi.Load([]byte(`
+openapi: 3.0.3
+info: {title: Books, version: "1"}
+paths: {}
+components:
+  schemas:
+    Book:
+      type: object
+      x-internal: true
+      properties:
+        price: {type: number, exclusiveMinimum: true, minimum: 0}
+`))
+	assert.NoError(t, err)
+
+	book := doc.Components.Schemas["Book"]
+	assert.Equal(t, true, book.Extensions["x-internal"])
+	assert.Equal(t, true, book.Properties["price"].Extensions["exclusiveMinimum"])
+
+	out, err := json.Marshal(doc)
+	assert.NoError(t, err)
+	assert.Contains(t, string(out), `"x-internal":true`)
+	assert.Contains(t, string(out), `"exclusiveMinimum":true`)
+}
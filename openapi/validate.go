@@ -0,0 +1,265 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// ValidationError is a value that does not match its schema.
+type ValidationError struct {
+	// Path locates the value, e.g. $.book.title.
+	Path    string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Path + ": " + e.Message
+}
+
+// Validate checks a value decoded by encoding/json into an interface{}
+// against schema, resolving references to the components of the document.
+// It supports the keywords the generator emits and returns every violation
+// found. A oneOf is satisfied by any of its alternatives unless it has a
+// discriminator, since alternatives generated from Go types often overlap.
+func (d *Document) Validate(schema *Schema, value interface{}) []*ValidationError {
+	v := &validator{doc: d}
+	v.validate("$", schema, value, 0)
+	return v.errors
+}
+
+type validator struct {
+	doc    *Document
+	errors []*ValidationError
+}
+
+// maxDepth bounds reference chains, for schemas that refer to themselves
+// without ever reaching a value.
+const maxDepth = 64
+
+func (v *validator) fail(path, format string, args ...interface{}) {
+	v.errors = append(v.errors, &ValidationError{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// matches reports whether value satisfies schema, without recording errors.
+func (v *validator) matches(path string, schema *Schema, value interface{}, depth int) bool {
+	sub := &validator{doc: v.doc}
+	sub.validate(path, schema, value, depth)
+	return len(sub.errors) == 0
+}
+
+func (v *validator) resolve(ref string) *Schema {
+	name := strings.TrimPrefix(ref, "#/components/schemas/")
+	if v.doc.Components == nil || name == ref {
+		return nil
+	}
+	return v.doc.Components.Schemas[name]
+}
+
+func (v *validator) validate(path string, schema *Schema, value interface{}, depth int) {
+	if schema == nil {
+		return
+	}
+	if depth > maxDepth {
+		v.fail(path, "schema nesting is too deep")
+		return
+	}
+
+	if schema.Ref != "" {
+		target := v.resolve(schema.Ref)
+		if target == nil {
+			v.fail(path, "unresolved reference %s", schema.Ref)
+			return
+		}
+		// In 3.1, keywords next to $ref apply as well.
+		sibling := *schema
+		sibling.Ref = ""
+		v.validate(path, target, value, depth+1)
+		v.validate(path, &sibling, value, depth+1)
+		return
+	}
+
+	if value == nil && (schema.Nullable || schema.Type.Is("null")) {
+		return
+	}
+
+	for _, sub := range schema.AllOf {
+		v.validate(path, sub, value, depth+1)
+	}
+	if len(schema.OneOf) > 0 {
+		v.validateOneOf(path, schema, value, depth)
+	}
+	if len(schema.AnyOf) > 0 && !v.anyMatch(path, schema.AnyOf, value, depth) {
+		v.fail(path, "does not match any of the alternatives")
+	}
+	if schema.Not != nil && v.matches(path, schema.Not, value, depth+1) {
+		v.fail(path, "must not match the schema")
+	}
+
+	if schema.Enum != nil && !containsJSON(schema.Enum, value) {
+		v.fail(path, "must be one of %s", jsonString(schema.Enum))
+	}
+	if schema.Const != nil && !equalJSON(schema.Const, value) {
+		v.fail(path, "must be %s", jsonString(schema.Const))
+	}
+
+	if schema.Type != nil && !v.checkType(path, schema, value) {
+		return
+	}
+
+	switch value := value.(type) {
+	case map[string]interface{}:
+		v.validateObject(path, schema, value, depth)
+	case []interface{}:
+		v.validateArray(path, schema, value, depth)
+	case string:
+		v.validateString(path, schema, value)
+	case float64:
+		v.validateNumber(path, schema, value)
+	}
+}
+
+func (v *validator) validateOneOf(path string, schema *Schema, value interface{}, depth int) {
+	if schema.Discriminator != nil {
+		object, _ := value.(map[string]interface{})
+		property, ok := object[schema.Discriminator.PropertyName].(string)
+		if !ok {
+			v.fail(path, "missing discriminator property %q", schema.Discriminator.PropertyName)
+			return
+		}
+
+		ref, ok := schema.Discriminator.Mapping[property]
+		if !ok {
+			ref = "#/components/schemas/" + property
+		}
+		for _, sub := range schema.OneOf {
+			if sub.Ref == ref {
+				v.validate(path, sub, value, depth+1)
+				return
+			}
+		}
+		v.fail(path, "unknown discriminator value %q", property)
+		return
+	}
+
+	if !v.anyMatch(path, schema.OneOf, value, depth) {
+		v.fail(path, "does not match any of the alternatives")
+	}
+}
+
+func (v *validator) anyMatch(path string, schemas []*Schema, value interface{}, depth int) bool {
+	for _, sub := range schemas {
+		if v.matches(path, sub, value, depth+1) {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *validator) checkType(path string, schema *Schema, value interface{}) bool {
+	actual := jsonType(value)
+	for _, typ := range schema.Type {
+		if typ == actual || typ == "number" && actual == "integer" {
+			return true
+		}
+	}
+	v.fail(path, "expected %s, got %s", strings.Join(schema.Type, " or "), actual)
+	return false
+}
+
+func jsonType(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if value == math.Trunc(value) && !math.IsInf(value, 0) {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func (v *validator) validateObject(path string, schema *Schema, object map[string]interface{}, depth int) {
+	for _, name := range schema.Required {
+		if _, ok := object[name]; !ok {
+			v.fail(path, "missing required property %q", name)
+		}
+	}
+
+	for _, name := range sortedKeys(object) {
+		child := path + "." + name
+		if property, ok := schema.Properties[name]; ok {
+			v.validate(child, property, object[name], depth+1)
+		} else if schema.AdditionalProperties != nil {
+			v.validate(child, schema.AdditionalProperties, object[name], depth+1)
+		}
+	}
+}
+
+func (v *validator) validateArray(path string, schema *Schema, array []interface{}, depth int) {
+	if schema.MinItems != nil && len(array) < *schema.MinItems {
+		v.fail(path, "must have at least %d items", *schema.MinItems)
+	}
+	if schema.MaxItems != nil && len(array) > *schema.MaxItems {
+		v.fail(path, "must have at most %d items", *schema.MaxItems)
+	}
+	for i, item := range array {
+		v.validate(fmt.Sprintf("%s[%d]", path, i), schema.Items, item, depth+1)
+	}
+}
+
+func (v *validator) validateString(path string, schema *Schema, s string) {
+	length := utf8.RuneCountInString(s)
+	if schema.MinLength != nil && length < *schema.MinLength {
+		v.fail(path, "must be at least %d characters", *schema.MinLength)
+	}
+	if schema.MaxLength != nil && length > *schema.MaxLength {
+		v.fail(path, "must be at most %d characters", *schema.MaxLength)
+	}
+	if schema.Pattern != "" {
+		if re, err := regexp.Compile(schema.Pattern); err == nil && !re.MatchString(s) {
+			v.fail(path, "must match %s", schema.Pattern)
+		}
+	}
+}
+
+func (v *validator) validateNumber(path string, schema *Schema, f float64) {
+	if schema.Minimum != nil && f < *schema.Minimum {
+		v.fail(path, "must be at least %v", *schema.Minimum)
+	}
+	if schema.Maximum != nil && f > *schema.Maximum {
+		v.fail(path, "must be at most %v", *schema.Maximum)
+	}
+}
+
+func containsJSON(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if equalJSON(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// equalJSON compares values by their JSON encoding, so that an int64 enum
+// value equals the float64 it decodes to.
+func equalJSON(a, b interface{}) bool {
+	return jsonString(a) == jsonString(b)
+}
+
+func jsonString(v interface{}) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
@@ -0,0 +1,57 @@
+package openapi_test
+
+import (
+	"testing"
+
+	"github.com/sattvikc/go-simpleapi/openapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func messages(errs []*openapi.ValidationError) []string {
+	result := []string{}
+	for _, err := range errs {
+		result = append(result, err.Error())
+	}
+	return result
+}
+
+func TestValidate(t *testing.T) {
+	doc := &openapi.Document{
+		Components: &openapi.Components{Schemas: map[string]*openapi.Schema{
+			"OK": {
+				Type:     openapi.Types{"object"},
+				Required: []string{"status", "tags"},
+				Properties: map[string]*openapi.Schema{
+					"status": {Type: openapi.Types{"string"}, Enum: []interface{}{"OK"}},
+					"tags":   {Type: openapi.Types{"array"}, Items: &openapi.Schema{Type: openapi.Types{"string"}}, MaxItems: openapi.Int(2)},
+					"note":   {Type: openapi.Types{"string"}, Nullable: true},
+					"count":  {Type: openapi.Types{"integer"}, Minimum: openapi.Float(0)},
+				},
+			},
+			"Exists": {
+				Type:       openapi.Types{"object"},
+				Required:   []string{"status"},
+				Properties: map[string]*openapi.Schema{"status": {Type: openapi.Types{"string"}}},
+			},
+		}},
+	}
+	schema := &openapi.Schema{
+		OneOf:         []*openapi.Schema{openapi.RefTo("OK"), openapi.RefTo("Exists")},
+		Discriminator: &openapi.Discriminator{PropertyName: "status", Mapping: map[string]string{"EXISTS": "#/components/schemas/Exists"}},
+	}
+
+	assert.Empty(t, doc.Validate(schema, map[string]interface{}{"status": "OK", "tags": []interface{}{"a"}, "note": nil, "count": float64(3)}))
+	assert.Empty(t, doc.Validate(schema, map[string]interface{}{"status": "EXISTS"}))
+
+	assert.Equal(t, []string{
+		`$: missing required property "tags"`,
+		"$.count: expected integer, got number",
+	}, messages(doc.Validate(schema, map[string]interface{}{"status": "OK", "count": 1.5})))
+	assert.Equal(t, []string{
+		"$.count: must be at least 0",
+		"$.tags: must have at most 2 items",
+		"$.tags[1]: expected string, got integer",
+	}, messages(doc.Validate(schema, map[string]interface{}{"status": "OK", "tags": []interface{}{"a", float64(1), "c"}, "count": float64(-1)})))
+	assert.Equal(t, []string{`$: unknown discriminator value "GONE"`}, messages(doc.Validate(schema, map[string]interface{}{"status": "GONE"})))
+	assert.Equal(t, []string{`$: missing discriminator property "status"`}, messages(doc.Validate(schema, "OK")))
+}
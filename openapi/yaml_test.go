@@ -0,0 +1,67 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/sattvikc/go-simpleapi/openapi"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestYAMLRoundTrip(t *testing.T) {
+	doc := &openapi.Document{
+		OpenAPI: "3.0.0",
+		Info:    openapi.Info{Title: "Books", Version: "1.0.0"},
+		Paths: map[string]*openapi.PathItem{
+			"/books/{id}": {
+				Get: &openapi.Operation{
+					Tags:       []string{},
+					Parameters: []*openapi.Parameter{{Name: "id", In: "path", Required: true, Schema: &openapi.Schema{Type: openapi.Types{"integer"}}}},
+					Responses: map[string]*openapi.Response{
+						"200": {
+							Description: "OK",
+							Content: map[string]*openapi.MediaType{
+								"application/json": {Schema: &openapi.Schema{
+									Type:     openapi.Types{"string", "null"},
+									Example:  "true",
+									Maximum:  openapi.Float(1.5),
+									MinItems: openapi.Int(2),
+								}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	data, err := yaml.Marshal(doc)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "openapi: 3.0.0\ninfo:\n    title: Books\n")
+	assert.Contains(t, string(data), `"200":`)
+	assert.Contains(t, string(data), `example: "true"`)
+
+	decoded := &openapi.Document{}
+	assert.NoError(t, yaml.Unmarshal(data, decoded))
+
+	expected, _ := json.Marshal(doc)
+	actual, _ := json.Marshal(decoded)
+	assert.JSONEq(t, string(expected), string(actual))
+}
+
+func TestUnmarshalYAMLWithUnquotedCodes(t *testing.T) {
+	doc := &openapi.Document{}
+	assert.NoError(t, yaml.Unmarshal([]byte(`
+openapi: 3.0.3
+info: {title: Books, version: "1"}
+paths:
+  /books:
+    get:
+      responses:
+        200:
+          description: OK
+`), doc))
+
+	assert.Equal(t, "OK", doc.Paths["/books"].Get.Responses["200"].Description)
+}
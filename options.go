@@ -0,0 +1,79 @@
+package simpleapi
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sattvikc/go-simpleapi/openapi"
+	"github.com/sattvikc/go-simpleapi/websocket"
+)
+
+// Option configures an App created with New.
+type Option func(s *App)
+
+type (
+	Info         = openapi.Info
+	Contact      = openapi.Contact
+	License      = openapi.License
+	Server       = openapi.Server
+	Tag          = openapi.Tag
+	ExternalDocs = openapi.ExternalDocs
+)
+
+// WithInfo sets the info object of the OpenAPI document. An empty title or
+// version keeps the default.
+func WithInfo(info Info) Option {
+	return func(s *App) {
+		if info.Title == "" {
+			info.Title = s.doc.Info.Title
+		}
+		if info.Version == "" {
+			info.Version = s.doc.Info.Version
+		}
+		s.doc.Info = info
+	}
+}
+
+// WithServers lists the servers the API is available on.
+func WithServers(servers ...Server) Option {
+	return func(s *App) {
+		s.doc.Servers = servers
+	}
+}
+
+// WithTags describes the tags used by endpoints. Swagger UI groups operations
+// in the order the tags are given here.
+func WithTags(tags ...Tag) Option {
+	return func(s *App) {
+		s.doc.Tags = tags
+	}
+}
+
+// WithExternalDocs links additional documentation for the whole API.
+func WithExternalDocs(docs ExternalDocs) Option {
+	return func(s *App) {
+		s.doc.ExternalDocs = &docs
+	}
+}
+
+// WithOpenAPIVersion sets the version of the generated document. Versions
+// 3.0.x and 3.1.x are supported; 3.1 documents use JSON Schema 2020-12, see
+// openapi.Document.ConvertTo31.
+func WithOpenAPIVersion(version string) Option {
+	if !strings.HasPrefix(version, "3.0.") && !strings.HasPrefix(version, "3.1.") {
+		panic(fmt.Sprintf("unsupported OpenAPI version %q", version))
+	}
+	return func(s *App) {
+		s.doc.OpenAPI = version
+	}
+}
+
+// WithWebSocketOrigins accepts websocket upgrades from other origins than the
+// host of the request, like "https://app.example.com", or from any with "*".
+// By default they are refused, so that other websites cannot open connections
+// with the cookies of a user.
+func WithWebSocketOrigins(origins ...string) Option {
+	return func(s *App) {
+		s.upgrader.CheckOrigin = websocket.AllowOrigins(origins...)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"reflect"
 	"strconv"
+	"time"
 
 	"github.com/sattvikc/go-simpleapi/router"
 )
@@ -23,7 +24,7 @@ func PopulateValueFromTypeUsingContext(request *http.Request, params router.Para
 
 		} else if pType.Field(i).Tag.Get("body") == "urlencoded" {
 			for j := 0; j < pVal.Field(i).NumField(); j++ {
-				err := setValue(pVal.Field(i).Field(j), request.FormValue(pType.Field(i).Type.Field(j).Tag.Get("form")))
+				err := SetValue(pVal.Field(i).Field(j), request.FormValue(pType.Field(i).Type.Field(j).Tag.Get("form")))
 				if err != nil {
 					return err
 				}
@@ -41,7 +42,7 @@ func PopulateValueFromTypeUsingContext(request *http.Request, params router.Para
 					pVal.Field(i).Field(j).Set(reflect.ValueOf(file))
 
 				} else {
-					err := setValue(pVal.Field(i).Field(j), request.FormValue(pType.Field(i).Type.Field(j).Tag.Get("form")))
+					err := SetValue(pVal.Field(i).Field(j), request.FormValue(pType.Field(i).Type.Field(j).Tag.Get("form")))
 					if err != nil {
 						return err
 					}
@@ -55,14 +56,14 @@ func PopulateValueFromTypeUsingContext(request *http.Request, params router.Para
 			}
 
 		} else if pType.Field(i).Tag.Get("path") != "" {
-			err := setValue(pVal.Field(i), params.ByName(pType.Field(i).Tag.Get("path")))
+			err := SetValue(pVal.Field(i), params.ByName(pType.Field(i).Tag.Get("path")))
 			if err != nil {
 				return err
 			}
 
 		} else if pType.Field(i).Tag.Get("query") != "" {
 			if pVal.Field(i).Type().Kind() != reflect.Ptr || request.URL.Query().Get(pType.Field(i).Tag.Get("query")) != "" {
-				err := setValue(pVal.Field(i), request.URL.Query().Get(pType.Field(i).Tag.Get("query")))
+				err := SetValue(pVal.Field(i), request.URL.Query().Get(pType.Field(i).Tag.Get("query")))
 				if err != nil {
 					return err
 				}
@@ -70,7 +71,7 @@ func PopulateValueFromTypeUsingContext(request *http.Request, params router.Para
 
 		} else if pType.Field(i).Tag.Get("header") != "" {
 			if pVal.Field(i).Type().Kind() != reflect.Ptr || request.Header.Get(pType.Field(i).Tag.Get("header")) != "" {
-				err := setValue(pVal.Field(i), request.Header.Get(pType.Field(i).Tag.Get("header")))
+				err := SetValue(pVal.Field(i), request.Header.Get(pType.Field(i).Tag.Get("header")))
 				if err != nil {
 					return err
 				}
@@ -81,13 +82,28 @@ func PopulateValueFromTypeUsingContext(request *http.Request, params router.Para
 	return nil
 }
 
-func setValue(valueObj reflect.Value, value string) error {
+// SetValue parses value into valueObj, allocating it first when it is a
+// pointer. Numbers, booleans, strings and times are supported.
+func SetValue(valueObj reflect.Value, value string) error {
 
 	if valueObj.Kind() == reflect.Ptr {
 		valueObj.Set(reflect.New(valueObj.Type().Elem()))
 		valueObj = valueObj.Elem()
 	}
 
+	if valueObj.Type() == timeType {
+		// Headers use the HTTP date format, everything else RFC 3339.
+		timeValue, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			timeValue, err = http.ParseTime(value)
+		}
+		if err != nil {
+			return err
+		}
+		valueObj.Set(reflect.ValueOf(timeValue))
+		return nil
+	}
+
 	switch valueObj.Kind() {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		// Parse value as integer
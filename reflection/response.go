@@ -0,0 +1,126 @@
+package reflection
+
+import (
+	"encoding"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// ResponseFields are the fields of a response struct: those tagged
+// header:"..." are sent as headers and the one tagged body:"json" is encoded
+// as the body.
+type ResponseFields struct {
+	Headers []reflect.StructField
+	Body    *reflect.StructField
+}
+
+// GetResponseFields returns the header and body fields of a response struct
+// type, or a pointer to one. Only structs with a body:"json" field are
+// response structs, so that request structs and other values with header
+// tags are not reinterpreted; ok is false for anything else, in which case
+// the whole value is the body.
+func GetResponseFields(t reflect.Type) (fields ResponseFields, ok bool) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return fields, false
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if field.Tag.Get("header") != "" {
+			fields.Headers = append(fields.Headers, field)
+		} else if field.Tag.Get("body") == "json" && fields.Body == nil {
+			fields.Body = &field
+		}
+	}
+
+	if fields.Body == nil {
+		return ResponseFields{}, false
+	}
+	return fields, true
+}
+
+// WriteResponseHeaders sets the headers declared by the response struct v and
+// returns the value of its body field. Nil pointers, empty strings and empty
+// slices are not sent.
+func WriteResponseHeaders(header http.Header, v reflect.Value) (reflect.Value, error) {
+	fields, _ := GetResponseFields(v.Type())
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+
+	for _, field := range fields.Headers {
+		name := field.Tag.Get("header")
+		value := v.FieldByIndex(field.Index)
+
+		if value.Kind() == reflect.Slice && value.Type() != reflect.TypeOf([]byte(nil)) {
+			for i := 0; i < value.Len(); i++ {
+				s, err := FormatValue(value.Index(i))
+				if err != nil {
+					return reflect.Value{}, fmt.Errorf("header %s: %w", name, err)
+				}
+				header.Add(name, s)
+			}
+			continue
+		}
+
+		if value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				continue
+			}
+			value = value.Elem()
+		}
+		s, err := FormatValue(value)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("header %s: %w", name, err)
+		}
+		if s != "" {
+			header.Set(name, s)
+		}
+	}
+
+	return v.FieldByIndex(fields.Body.Index), nil
+}
+
+// FormatValue is the inverse of SetValue: it formats a number, boolean,
+// string, time or text marshaler for a header, query or form value.
+func FormatValue(v reflect.Value) (string, error) {
+	if v.Type() == timeType {
+		return v.Interface().(time.Time).UTC().Format(http.TimeFormat), nil
+	}
+	if v.Type().Implements(textMarshalerType) {
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text), err
+	}
+	if s, ok := v.Interface().(fmt.Stringer); ok {
+		return s.String(), nil
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits()), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.String:
+		return v.String(), nil
+	default:
+		return "", fmt.Errorf("unsupported type: %v", v.Kind())
+	}
+}
@@ -0,0 +1,110 @@
+package simpleapi
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"net"
+	"net/http"
+)
+
+// ResponseWriter is the http.ResponseWriter handed to handlers. It records
+// what has been written so that middleware can inspect the outcome of the
+// handlers that ran after it.
+type ResponseWriter interface {
+	http.ResponseWriter
+	http.Flusher
+	http.Hijacker
+	http.Pusher
+
+	// Status returns the status code written, or 0 if nothing is written yet.
+	Status() int
+	// Size returns the number of body bytes written.
+	Size() int
+	// Written reports whether the status line has been sent.
+	Written() bool
+	// Unwrap returns the underlying writer, for use by http.ResponseController.
+	Unwrap() http.ResponseWriter
+}
+
+type responseWriter struct {
+	http.ResponseWriter
+	status  int
+	size    int
+	written bool
+	// capture receives a copy of JSON bodies when responses are validated.
+	capture *bytes.Buffer
+}
+
+func newResponseWriter(w http.ResponseWriter) *responseWriter {
+	return &responseWriter{ResponseWriter: w}
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if w.written {
+		return
+	}
+	w.status = status
+	w.written = true
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if !w.written {
+		w.WriteHeader(http.StatusOK)
+	}
+	n, err := w.ResponseWriter.Write(b)
+	if w.capture != nil && isJSON(w.Header().Get("Content-Type")) {
+		w.capture.Write(b[:n])
+	}
+	w.size += n
+	return n, err
+}
+
+func (w *responseWriter) Flush() {
+	flusher, ok := w.ResponseWriter.(http.Flusher)
+	if !ok {
+		return
+	}
+	if !w.written {
+		w.WriteHeader(http.StatusOK)
+	}
+	flusher.Flush()
+}
+
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer does not implement http.Hijacker")
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err == nil && !w.written {
+		w.status = http.StatusSwitchingProtocols
+		w.written = true
+	}
+	return conn, rw, err
+}
+
+func (w *responseWriter) Push(target string, opts *http.PushOptions) error {
+	pusher, ok := w.ResponseWriter.(http.Pusher)
+	if !ok {
+		return http.ErrNotSupported
+	}
+	return pusher.Push(target, opts)
+}
+
+func (w *responseWriter) Status() int {
+	return w.status
+}
+
+func (w *responseWriter) Size() int {
+	return w.size
+}
+
+func (w *responseWriter) Written() bool {
+	return w.written
+}
+
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
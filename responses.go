@@ -0,0 +1,54 @@
+package simpleapi
+
+// ResponseOption configures a response declared with Endpoint.WithResponse
+// or Endpoint.WithEventStream.
+type ResponseOption func(r *responseType)
+
+type responseExample struct {
+	name  string
+	value interface{}
+}
+
+type responseHeader struct {
+	name        string
+	value       interface{}
+	description string
+}
+
+// ResponseVariant names one of several responses declared for the same
+// status code. With Endpoint.WithDiscriminator, the name is the value of the
+// discriminator property that selects this response.
+func ResponseVariant(name string) ResponseOption {
+	return func(r *responseType) {
+		r.variant = name
+	}
+}
+
+// ResponseExample adds a named example of the response body.
+func ResponseExample(name string, value interface{}) ResponseOption {
+	return func(r *responseType) {
+		r.examples = append(r.examples, responseExample{name: name, value: value})
+	}
+}
+
+// ResponseHeader documents a header sent with the response. Its schema is
+// derived from the type of value, e.g. "" for a string or 0 for an integer;
+// a nil value documents a string.
+func ResponseHeader(name string, value interface{}, description string) ResponseOption {
+	if value == nil {
+		value = ""
+	}
+	return func(r *responseType) {
+		r.headers = append(r.headers, responseHeader{name: name, value: value, description: description})
+	}
+}
+
+// WithDiscriminator documents that the responses declared for code are told
+// apart by the value of property, as named with ResponseVariant.
+func (e *Endpoint) WithDiscriminator(code int, property string) *Endpoint {
+	if e.discriminators == nil {
+		e.discriminators = map[int]string{}
+	}
+	e.discriminators[code] = property
+	return e
+}
@@ -0,0 +1,82 @@
+package simpleapi
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Route describes an endpoint registered with App.Endpoint, for tools that
+// generate code from an App.
+type Route struct {
+	// Method is the upper case HTTP method.
+	Method      string
+	Path        string
+	OperationID string
+	Summary     string
+	Description string
+	Deprecated  bool
+	WebSocket   bool
+	// Requests are the request structs bound by the handlers of the endpoint
+	// and by the providers they depend on, in the order they are bound.
+	Requests  []reflect.Type
+	Responses []RouteResponse
+}
+
+// RouteResponse is a response declared with Endpoint.WithResponse or
+// Endpoint.WithEventStream.
+type RouteResponse struct {
+	Code int
+	// Type is the Go type of the response, or nil when it has no body.
+	Type        reflect.Type
+	ContentType string
+	Description string
+	Variant     string
+}
+
+// Routes returns the endpoints registered so far, in registration order.
+func (s *App) Routes() []Route {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	routes := []Route{}
+	for _, e := range s.endpoints {
+		route := Route{
+			Method:      strings.ToUpper(e.method),
+			Path:        e.path,
+			OperationID: e.operationID,
+			Summary:     e.summary,
+			Description: e.description,
+			Deprecated:  e.deprecated,
+			WebSocket:   e.websocket,
+			Requests:    []reflect.Type{},
+			Responses:   []RouteResponse{},
+		}
+
+		seen := map[reflect.Type]bool{}
+		added := map[reflect.Type]bool{}
+		for _, h := range *e.handlerInstances {
+			for _, t := range s.requestParamTypes(h.ParamTypes, seen) {
+				if !added[t] {
+					added[t] = true
+					route.Requests = append(route.Requests, t)
+				}
+			}
+		}
+
+		for _, r := range e.responseTypes {
+			response := RouteResponse{
+				Code:        r.code,
+				ContentType: r.contentType,
+				Description: r.description,
+				Variant:     r.variant,
+			}
+			if r.response != nil {
+				response.Type = reflect.TypeOf(r.response)
+			}
+			route.Responses = append(route.Responses, response)
+		}
+
+		routes = append(routes, route)
+	}
+	return routes
+}
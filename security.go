@@ -0,0 +1,97 @@
+package simpleapi
+
+import "github.com/sattvikc/go-simpleapi/openapi"
+
+// SecurityScheme describes how clients authenticate. Use the constructors
+// below rather than filling it in by hand.
+type SecurityScheme = openapi.SecurityScheme
+
+type (
+	OAuthFlows = openapi.OAuthFlows
+	OAuthFlow  = openapi.OAuthFlow
+)
+
+// SecurityRequirement maps security scheme names to the scopes required. All
+// schemes in one requirement must be satisfied together.
+type SecurityRequirement = openapi.SecurityRequirement
+
+// HTTPBearer is an Authorization: Bearer token. The format, e.g. "JWT", is
+// only a hint for documentation.
+func HTTPBearer(format string) SecurityScheme {
+	return SecurityScheme{Type: "http", Scheme: "bearer", BearerFormat: format}
+}
+
+// HTTPBasic is HTTP basic authentication.
+func HTTPBasic() SecurityScheme {
+	return SecurityScheme{Type: "http", Scheme: "basic"}
+}
+
+// APIKeyHeader is an API key sent in the named header.
+func APIKeyHeader(name string) SecurityScheme {
+	return SecurityScheme{Type: "apiKey", In: "header", Name: name}
+}
+
+// APIKeyQuery is an API key sent in the named query parameter.
+func APIKeyQuery(name string) SecurityScheme {
+	return SecurityScheme{Type: "apiKey", In: "query", Name: name}
+}
+
+// APIKeyCookie is an API key sent in the named cookie.
+func APIKeyCookie(name string) SecurityScheme {
+	return SecurityScheme{Type: "apiKey", In: "cookie", Name: name}
+}
+
+// OAuth2 is OAuth 2.0 with the given flows. Swagger UI redirects back to
+// /docs/oauth2-redirect, which must be registered with the provider.
+func OAuth2(flows OAuthFlows) SecurityScheme {
+	return SecurityScheme{Type: "oauth2", Flows: &flows}
+}
+
+// OpenIDConnect is OpenID Connect discovery at the given URL.
+func OpenIDConnect(url string) SecurityScheme {
+	return SecurityScheme{Type: "openIdConnect", OpenIDConnectURL: url}
+}
+
+// WithSecurityScheme declares a security scheme under components, so that
+// endpoints can require it by name.
+func WithSecurityScheme(name string, scheme SecurityScheme) Option {
+	return func(s *App) {
+		s.securitySchemes[name] = &scheme
+	}
+}
+
+// WithSecurity requires the named scheme for every endpoint that does not
+// declare its own security. Calling it again adds an alternative.
+func WithSecurity(name string, scopes ...string) Option {
+	return func(s *App) {
+		s.doc.Security = append(s.doc.Security, SecurityRequirement{name: nonNil(scopes)})
+	}
+}
+
+// WithSecurity requires the named scheme for this endpoint. Calling it again
+// adds an alternative; use WithSecurityRequirement to require several schemes
+// together. Builders shared by several endpoints, like an auth builder, can
+// call it to apply to all of them.
+func (e *Endpoint) WithSecurity(name string, scopes ...string) *Endpoint {
+	return e.WithSecurityRequirement(SecurityRequirement{name: nonNil(scopes)})
+}
+
+// WithSecurityRequirement adds an alternative security requirement.
+func (e *Endpoint) WithSecurityRequirement(requirement SecurityRequirement) *Endpoint {
+	e.security = append(e.security, requirement)
+	return e
+}
+
+// WithoutSecurity marks the endpoint as public, overriding WithSecurity on
+// the App.
+func (e *Endpoint) WithoutSecurity() *Endpoint {
+	e.security = []SecurityRequirement{}
+	return e
+}
+
+func nonNil(scopes []string) []string {
+	if scopes == nil {
+		return []string{}
+	}
+	return scopes
+}
@@ -0,0 +1,393 @@
+// Package simpleapitest tests the endpoints of an App in-process, without
+// binding a port:
+//
+//	c := simpleapitest.New(app)
+//	c.POST("/books").JSON(book).Header("Authorization", "Bearer token").
+//		Expect(t).Status(200).JSONPath("status", "OK")
+//
+// Requests are served by App.ServeHTTP. Cookies set by responses are sent
+// with later requests of the same client, like a browser would.
+package simpleapitest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/sattvikc/go-simpleapi"
+)
+
+// baseURL is the origin of the requests, for the cookie jar.
+var baseURL = &url.URL{Scheme: "http", Host: "example.com"}
+
+// Client sends requests to an App.
+type Client struct {
+	app *simpleapi.App
+	jar *cookiejar.Jar
+	// Header is sent with every request of the client.
+	Header http.Header
+}
+
+// New returns a client for app.
+func New(app *simpleapi.App) *Client {
+	jar, _ := cookiejar.New(nil)
+	return &Client{app: app, jar: jar, Header: http.Header{}}
+}
+
+// Override replaces the provider of a dependency, like App.Override, until
+// the test ends. Only this override is then removed, but as overrides apply
+// to every request, tests that override the same App must not run in
+// parallel.
+func (c *Client) Override(t testing.TB, provider interface{}) {
+	t.Cleanup(c.app.Override(provider))
+}
+
+// OverrideEndpoint replaces the handlers of an endpoint, like
+// App.OverrideEndpoint, until the test ends. The same caveat as Override
+// applies.
+func (c *Client) OverrideEndpoint(t testing.TB, path string, builders ...func(e *simpleapi.Endpoint) interface{}) {
+	t.Cleanup(c.app.OverrideEndpoint(path, builders...))
+}
+
+// GET, POST, PUT, DELETE, PATCH and HEAD start a request with the method.
+func (c *Client) GET(path string) *Request    { return c.Request(http.MethodGet, path) }
+func (c *Client) POST(path string) *Request   { return c.Request(http.MethodPost, path) }
+func (c *Client) PUT(path string) *Request    { return c.Request(http.MethodPut, path) }
+func (c *Client) DELETE(path string) *Request { return c.Request(http.MethodDelete, path) }
+func (c *Client) PATCH(path string) *Request  { return c.Request(http.MethodPatch, path) }
+func (c *Client) HEAD(path string) *Request   { return c.Request(http.MethodHead, path) }
+
+// Request starts a request. The path may include a query string.
+func (c *Client) Request(method, path string) *Request {
+	return &Request{
+		client: c,
+		method: method,
+		path:   path,
+		query:  url.Values{},
+		header: http.Header{},
+		form:   url.Values{},
+	}
+}
+
+// Request is a request being built. Its methods return the request, so that
+// calls can be chained; the first error is reported by Expect.
+type Request struct {
+	client      *Client
+	method      string
+	path        string
+	query       url.Values
+	header      http.Header
+	cookies     []*http.Cookie
+	body        []byte
+	contentType string
+	form        url.Values
+	files       []file
+	err         error
+}
+
+type file struct {
+	field    string
+	filename string
+	content  []byte
+}
+
+// Query adds a query parameter.
+func (r *Request) Query(name, value string) *Request {
+	r.query.Add(name, value)
+	return r
+}
+
+// Header sets a header.
+func (r *Request) Header(name, value string) *Request {
+	r.header.Set(name, value)
+	return r
+}
+
+// Cookie adds a cookie, in addition to those the client has received.
+func (r *Request) Cookie(name, value string) *Request {
+	r.cookies = append(r.cookies, &http.Cookie{Name: name, Value: value})
+	return r
+}
+
+// JSON sends v encoded as JSON.
+func (r *Request) JSON(v interface{}) *Request {
+	data, err := json.Marshal(v)
+	if err != nil && r.err == nil {
+		r.err = fmt.Errorf("encoding JSON body: %w", err)
+	}
+	return r.Body("application/json", data)
+}
+
+// Body sends data with the given content type.
+func (r *Request) Body(contentType string, data []byte) *Request {
+	r.body = data
+	r.contentType = contentType
+	return r
+}
+
+// Form adds a form field. The form is sent URL encoded, or as multipart
+// when a file is attached.
+func (r *Request) Form(name, value string) *Request {
+	r.form.Add(name, value)
+	return r
+}
+
+// File attaches a file to a multipart form.
+func (r *Request) File(field, filename string, content []byte) *Request {
+	r.files = append(r.files, file{field: field, filename: filename, content: content})
+	return r
+}
+
+// Do serves the request and returns the recorded response.
+func (r *Request) Do() (*http.Response, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	body, contentType, err := r.encodeBody()
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := url.Parse(r.path)
+	if err != nil {
+		return nil, err
+	}
+	target = baseURL.ResolveReference(target)
+	if len(r.query) > 0 {
+		query := target.Query()
+		for name, values := range r.query {
+			query[name] = append(query[name], values...)
+		}
+		target.RawQuery = query.Encode()
+	}
+	req := httptest.NewRequest(r.method, target.String(), bytes.NewReader(body))
+
+	for name, values := range r.client.Header {
+		req.Header[name] = values
+	}
+	for name, values := range r.header {
+		req.Header[name] = values
+	}
+	if contentType != "" && req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	for _, cookie := range append(r.client.jar.Cookies(req.URL), r.cookies...) {
+		req.AddCookie(cookie)
+	}
+
+	w := httptest.NewRecorder()
+	r.client.app.ServeHTTP(w, req)
+
+	resp := w.Result()
+	r.client.jar.SetCookies(req.URL, resp.Cookies())
+	return resp, nil
+}
+
+func (r *Request) encodeBody() ([]byte, string, error) {
+	switch {
+	case len(r.files) > 0:
+		var buf bytes.Buffer
+		w := multipart.NewWriter(&buf)
+		for name, values := range r.form {
+			for _, value := range values {
+				if err := w.WriteField(name, value); err != nil {
+					return nil, "", err
+				}
+			}
+		}
+		for _, f := range r.files {
+			part, err := w.CreateFormFile(f.field, f.filename)
+			if err != nil {
+				return nil, "", err
+			}
+			if _, err := part.Write(f.content); err != nil {
+				return nil, "", err
+			}
+		}
+		if err := w.Close(); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), w.FormDataContentType(), nil
+
+	case len(r.form) > 0:
+		return []byte(r.form.Encode()), "application/x-www-form-urlencoded", nil
+	}
+	return r.body, r.contentType, nil
+}
+
+// Expect serves the request and returns its response for assertions, which
+// report failures to t. The test fails immediately if the request cannot be
+// served.
+func (r *Request) Expect(t testing.TB) *Response {
+	t.Helper()
+
+	resp, err := r.Do()
+	if err != nil {
+		t.Fatalf("%s %s: %v", r.method, r.path, err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("%s %s: reading body: %v", r.method, r.path, err)
+	}
+
+	return &Response{t: t, name: r.method + " " + r.path, Response: resp, body: body}
+}
+
+// Response is a served response. Its assertion methods return the response,
+// so that they can be chained, and report failures with t.Errorf.
+type Response struct {
+	*http.Response
+	t    testing.TB
+	name string
+	body []byte
+}
+
+// BodyBytes returns the body of the response.
+func (r *Response) BodyBytes() []byte {
+	return r.body
+}
+
+// Status asserts the status code.
+func (r *Response) Status(code int) *Response {
+	r.t.Helper()
+	if r.StatusCode != code {
+		r.t.Errorf("%s: status is %d, expected %d; body: %s", r.name, r.StatusCode, code, r.body)
+	}
+	return r
+}
+
+// Header asserts the value of a header.
+func (r *Response) Header(name, value string) *Response {
+	r.t.Helper()
+	if got := r.Response.Header.Get(name); got != value {
+		r.t.Errorf("%s: header %s is %q, expected %q", r.name, name, got, value)
+	}
+	return r
+}
+
+// Cookie asserts the value of a cookie set by the response.
+func (r *Response) Cookie(name, value string) *Response {
+	r.t.Helper()
+	for _, cookie := range r.Cookies() {
+		if cookie.Name == name {
+			if cookie.Value != value {
+				r.t.Errorf("%s: cookie %s is %q, expected %q", r.name, name, cookie.Value, value)
+			}
+			return r
+		}
+	}
+	r.t.Errorf("%s: cookie %s is not set", r.name, name)
+	return r
+}
+
+// Body asserts the body.
+func (r *Response) Body(body string) *Response {
+	r.t.Helper()
+	if string(r.body) != body {
+		r.t.Errorf("%s: body is %q, expected %q", r.name, r.body, body)
+	}
+	return r
+}
+
+// JSON asserts that the body is the JSON encoding of expected, ignoring
+// formatting and the order of object keys.
+func (r *Response) JSON(expected interface{}) *Response {
+	r.t.Helper()
+	got, ok := r.decoded()
+	if !ok {
+		return r
+	}
+	if want := normalize(expected); !reflect.DeepEqual(got, want) {
+		r.t.Errorf("%s: body is %s, expected %s", r.name, r.body, encode(want))
+	}
+	return r
+}
+
+// JSONPath asserts the value at path in the JSON body. The path is made of
+// object keys and array indices separated by dots, like books.0.title.
+func (r *Response) JSONPath(path string, expected interface{}) *Response {
+	r.t.Helper()
+	got, ok := r.decoded()
+	if !ok {
+		return r
+	}
+
+	for _, key := range strings.Split(path, ".") {
+		switch v := got.(type) {
+		case map[string]interface{}:
+			value, found := v[key]
+			if !found {
+				r.t.Errorf("%s: %s: no key %q in body %s", r.name, path, key, r.body)
+				return r
+			}
+			got = value
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(v) {
+				r.t.Errorf("%s: %s: no index %q in body %s", r.name, path, key, r.body)
+				return r
+			}
+			got = v[i]
+		default:
+			r.t.Errorf("%s: %s: cannot index %s with %q", r.name, path, encode(v), key)
+			return r
+		}
+	}
+
+	if want := normalize(expected); !reflect.DeepEqual(got, want) {
+		r.t.Errorf("%s: %s is %s, expected %s", r.name, path, encode(got), encode(want))
+	}
+	return r
+}
+
+// Decode decodes the JSON body into v, failing the test if it cannot.
+func (r *Response) Decode(v interface{}) *Response {
+	r.t.Helper()
+	if err := json.Unmarshal(r.body, v); err != nil {
+		r.t.Fatalf("%s: decoding body %s: %v", r.name, r.body, err)
+	}
+	return r
+}
+
+func (r *Response) decoded() (interface{}, bool) {
+	r.t.Helper()
+	var v interface{}
+	if err := json.Unmarshal(r.body, &v); err != nil {
+		r.t.Errorf("%s: body is not JSON: %v; body: %s", r.name, err, r.body)
+		return nil, false
+	}
+	return v, true
+}
+
+// normalize converts v to the values encoding/json decodes, so that structs
+// and integers compare equal to a decoded body.
+func normalize(v interface{}) interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return v
+	}
+	return normalized
+}
+
+func encode(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
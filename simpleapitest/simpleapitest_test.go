@@ -0,0 +1,275 @@
+package simpleapitest_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"testing"
+
+	"github.com/sattvikc/go-simpleapi"
+	"github.com/sattvikc/go-simpleapi/simpleapitest"
+	"github.com/stretchr/testify/assert"
+)
+
+type book struct {
+	Title  string `json:"title"`
+	Author string `json:"author"`
+}
+
+type createBook struct {
+	Body   book   `body:"json"`
+	Draft  *bool  `query:"draft"`
+	Source string `header:"X-Source"`
+}
+
+type user struct {
+	Name string
+}
+
+type authHeader struct {
+	User string `header:"X-User"`
+}
+
+func newApp() *simpleapi.App {
+	app := simpleapi.New()
+	app.Provide(func(ctx *simpleapi.Context, h authHeader) (*user, error) {
+		if h.User == "" {
+			return nil, simpleapi.NewHTTPError(401, errors.New("unauthorised"))
+		}
+		return &user{Name: h.User}, nil
+	})
+
+	app.Endpoint("/books", func(e *simpleapi.Endpoint) interface{} {
+		e.POST()
+
+		return func(ctx *simpleapi.Context, req createBook) error {
+			return ctx.JSON(200, map[string]interface{}{
+				"status": "OK",
+				"book":   req.Body,
+				"draft":  req.Draft,
+				"source": req.Source,
+				"tags":   []string{"new"},
+			})
+		}
+	})
+
+	app.Endpoint("/me", func(e *simpleapi.Endpoint) interface{} {
+		e.GET()
+
+		return func(ctx *simpleapi.Context, u *user) error {
+			return ctx.JSON(200, map[string]string{"name": u.Name})
+		}
+	})
+
+	app.Endpoint("/login", func(e *simpleapi.Endpoint) interface{} {
+		e.POST()
+
+		return func(ctx *simpleapi.Context) error {
+			http.SetCookie(ctx.Response, &http.Cookie{Name: "session", Value: "abc", Path: "/"})
+			return ctx.JSON(200, map[string]string{"status": "OK"})
+		}
+	})
+
+	app.Endpoint("/session", func(e *simpleapi.Endpoint) interface{} {
+		e.GET()
+
+		return func(ctx *simpleapi.Context) error {
+			names := []string{}
+			for _, cookie := range ctx.Request.Cookies() {
+				names = append(names, cookie.Name+"="+cookie.Value)
+			}
+			return ctx.JSON(200, map[string]interface{}{"cookies": names})
+		}
+	})
+
+	app.Endpoint("/subscribe", func(e *simpleapi.Endpoint) interface{} {
+		e.POST()
+
+		return func(ctx *simpleapi.Context, req struct {
+			Form struct {
+				Email string `form:"email"`
+			} `body:"urlencoded"`
+		}) error {
+			return ctx.JSON(200, map[string]string{"email": req.Form.Email})
+		}
+	})
+
+	app.Endpoint("/upload", func(e *simpleapi.Endpoint) interface{} {
+		e.POST()
+
+		return func(ctx *simpleapi.Context, req struct {
+			Form struct {
+				Name string         `form:"name"`
+				File multipart.File `form:"file"`
+			} `body:"multipart"`
+		}) error {
+			content, err := io.ReadAll(req.Form.File)
+			if err != nil {
+				return err
+			}
+			return ctx.JSON(200, map[string]string{"name": req.Form.Name, "content": string(content)})
+		}
+	})
+
+	return app
+}
+
+func TestJSONRequest(t *testing.T) {
+	c := simpleapitest.New(newApp())
+
+	c.POST("/books").
+		JSON(book{Title: "Dune", Author: "Herbert"}).
+		Query("draft", "true").
+		Header("X-Source", "test").
+		Expect(t).
+		Status(200).
+		Header("Content-Type", "application/json").
+		JSONPath("status", "OK").
+		JSONPath("book.title", "Dune").
+		JSONPath("draft", true).
+		JSONPath("tags.0", "new").
+		JSON(map[string]interface{}{
+			"status": "OK",
+			"book":   book{Title: "Dune", Author: "Herbert"},
+			"draft":  true,
+			"source": "test",
+			"tags":   []string{"new"},
+		})
+
+	var body struct {
+		Book book `json:"book"`
+	}
+	c.POST("/books?draft=false").JSON(book{Title: "Emma"}).Expect(t).Status(200).
+		JSONPath("draft", false).
+		Decode(&body)
+	assert.Equal(t, "Emma", body.Book.Title)
+}
+
+func TestCookies(t *testing.T) {
+	c := simpleapitest.New(newApp())
+
+	c.GET("/session").Expect(t).JSONPath("cookies", []string{})
+	c.POST("/login").Expect(t).Status(200).Cookie("session", "abc")
+	c.GET("/session").Expect(t).JSONPath("cookies", []string{"session=abc"})
+	c.GET("/session").Cookie("theme", "dark").Expect(t).
+		JSONPath("cookies", []string{"session=abc", "theme=dark"})
+}
+
+func TestForms(t *testing.T) {
+	c := simpleapitest.New(newApp())
+
+	c.POST("/subscribe").Form("email", "user@example.com").Expect(t).
+		Status(200).
+		JSONPath("email", "user@example.com")
+
+	c.POST("/upload").
+		Form("name", "notes").
+		File("file", "notes.txt", []byte("hello")).
+		Expect(t).
+		Status(200).
+		JSON(map[string]string{"name": "notes", "content": "hello"})
+}
+
+func TestOverrides(t *testing.T) {
+	app := newApp()
+	c := simpleapitest.New(app)
+	c.Header.Set("X-User", "sattvik")
+
+	t.Run("provider", func(t *testing.T) {
+		c.Override(t, func(ctx *simpleapi.Context) (*user, error) {
+			return &user{Name: "test"}, nil
+		})
+		c.GET("/me").Expect(t).JSONPath("name", "test")
+	})
+
+	t.Run("endpoint", func(t *testing.T) {
+		c.OverrideEndpoint(t, "/books", func(e *simpleapi.Endpoint) interface{} {
+			e.POST()
+
+			return func(ctx *simpleapi.Context) error {
+				return ctx.JSON(201, map[string]string{"status": "stubbed"})
+			}
+		})
+		c.POST("/books").JSON(book{}).Expect(t).Status(201).JSONPath("status", "stubbed")
+	})
+
+	c.GET("/me").Expect(t).JSONPath("name", "sattvik")
+	c.POST("/books").JSON(book{}).Expect(t).Status(200).JSONPath("status", "OK")
+
+	assert.Panics(t, func() {
+		app.OverrideEndpoint("/missing", func(e *simpleapi.Endpoint) interface{} {
+			e.GET()
+			return func(ctx *simpleapi.Context) error { return nil }
+		})
+	})
+}
+
+// recorder records the failures of assertions.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestNestedOverrides(t *testing.T) {
+	c := simpleapitest.New(newApp())
+	c.Header.Set("X-User", "sattvik")
+
+	t.Run("parent", func(t *testing.T) {
+		c.Override(t, func(ctx *simpleapi.Context) (*user, error) {
+			return &user{Name: "parent"}, nil
+		})
+
+		t.Run("child", func(t *testing.T) {
+			c.Override(t, func(ctx *simpleapi.Context) (*user, error) {
+				return &user{Name: "child"}, nil
+			})
+			c.OverrideEndpoint(t, "/books", func(e *simpleapi.Endpoint) interface{} {
+				e.POST()
+
+				return func(ctx *simpleapi.Context) error {
+					return ctx.JSON(201, map[string]string{"status": "stubbed"})
+				}
+			})
+			c.GET("/me").Expect(t).JSONPath("name", "child")
+		})
+
+		// The child's cleanup leaves the parent's override in place.
+		c.GET("/me").Expect(t).JSONPath("name", "parent")
+		c.POST("/books").JSON(book{}).Expect(t).Status(200)
+	})
+
+	c.GET("/me").Expect(t).JSONPath("name", "sattvik")
+}
+
+func TestFailures(t *testing.T) {
+	c := simpleapitest.New(newApp())
+	r := &recorder{TB: t}
+
+	c.POST("/books").JSON(book{Title: "Dune"}).Expect(r).
+		Status(201).
+		Header("X-Missing", "value").
+		JSONPath("book.title", "Emma").
+		JSONPath("book.pages", 1).
+		JSONPath("tags.3", "old").
+		JSON(map[string]string{})
+	c.GET("/me").Expect(r).Status(401).Body("plain").Cookie("session", "abc")
+
+	assert.Equal(t, []string{
+		`POST /books: status is 200, expected 201; body: {"book":{"title":"Dune","author":""},"draft":null,"source":"","status":"OK","tags":["new"]}`,
+		`POST /books: header X-Missing is "", expected "value"`,
+		`POST /books: book.title is "Dune", expected "Emma"`,
+		`POST /books: book.pages: no key "pages" in body {"book":{"title":"Dune","author":""},"draft":null,"source":"","status":"OK","tags":["new"]}`,
+		`POST /books: tags.3: no index "3" in body {"book":{"title":"Dune","author":""},"draft":null,"source":"","status":"OK","tags":["new"]}`,
+		`POST /books: body is {"book":{"title":"Dune","author":""},"draft":null,"source":"","status":"OK","tags":["new"]}, expected {}`,
+		`GET /me: body is "{\"error\":\"unauthorised\"}", expected "plain"`,
+		`GET /me: cookie session is not set`,
+	}, r.errors)
+}
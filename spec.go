@@ -0,0 +1,396 @@
+package simpleapi
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sattvikc/go-simpleapi/openapi"
+	"github.com/sattvikc/go-simpleapi/reflection"
+	"github.com/sattvikc/go-simpleapi/swagger"
+	"gopkg.in/yaml.v3"
+)
+
+// specCache holds the serialized OpenAPI document. It is dropped whenever an
+// endpoint or provider is registered and rebuilt on the next request.
+type specCache struct {
+	doc  *openapi.Document
+	json *encodedSpec
+	yaml *encodedSpec
+}
+
+type encodedSpec struct {
+	contentType string
+	body        []byte
+	gzipped     []byte
+	etag        string
+}
+
+func newEncodedSpec(contentType string, body []byte) (*encodedSpec, error) {
+	var gzipped bytes.Buffer
+	zw := gzip.NewWriter(&gzipped)
+	if _, err := zw.Write(body); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(body)
+	return &encodedSpec{
+		contentType: contentType,
+		body:        body,
+		gzipped:     gzipped.Bytes(),
+		etag:        `"` + hex.EncodeToString(sum[:16]) + `"`,
+	}, nil
+}
+
+// serve writes the document, honouring If-None-Match and gzip encoding.
+func (e *encodedSpec) serve(ctx *Context) error {
+	header := ctx.Response.Header()
+	header.Set("ETag", e.etag)
+	header.Set("Vary", "Accept-Encoding")
+	header.Set("Cache-Control", "no-cache")
+
+	if match := ctx.Request.Header.Get("If-None-Match"); match != "" {
+		for _, tag := range strings.Split(match, ",") {
+			if tag = strings.TrimSpace(tag); tag == e.etag || tag == "*" {
+				ctx.Response.WriteHeader(http.StatusNotModified)
+				return nil
+			}
+		}
+	}
+
+	body := e.body
+	if acceptsGzip(ctx.Request) {
+		header.Set("Content-Encoding", "gzip")
+		body = e.gzipped
+	}
+
+	header.Set("Content-Type", e.contentType)
+	header.Set("Content-Length", strconv.Itoa(len(body)))
+	ctx.Response.WriteHeader(http.StatusOK)
+	if ctx.Request.Method == http.MethodHead {
+		return nil
+	}
+	_, err := ctx.Response.Write(body)
+	return err
+}
+
+func acceptsGzip(r *http.Request) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if strings.TrimSpace(coding) == "gzip" && strings.ReplaceAll(params, " ", "") != "q=0" {
+			return true
+		}
+	}
+	return false
+}
+
+// cachedSpec returns the serialized document, building it if anything has
+// been registered since it was last built.
+func (s *App) cachedSpec() (*specCache, error) {
+	s.mu.RLock()
+	spec := s.spec
+	s.mu.RUnlock()
+	if spec != nil {
+		return spec, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.spec != nil {
+		return s.spec, nil
+	}
+
+	doc := s.buildDocument()
+	for _, hook := range s.specHooks {
+		hook(doc)
+	}
+
+	body, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	jsonSpec, err := newEncodedSpec("application/json", body)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err = encodeYAML(body)
+	if err != nil {
+		return nil, err
+	}
+	yamlSpec, err := newEncodedSpec("application/yaml", body)
+	if err != nil {
+		return nil, err
+	}
+
+	s.spec = &specCache{doc: doc, json: jsonSpec, yaml: yamlSpec}
+	return s.spec, nil
+}
+
+func encodeYAML(jsonBody []byte) ([]byte, error) {
+	node, err := openapi.JSONToYAML(jsonBody)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(node); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteSpec writes the OpenAPI document in the given format, "json" or
+// "yaml", without serving it. The output only changes when the endpoints or
+// options do, so it can be committed and diffed.
+func (s *App) WriteSpec(w io.Writer, format string) error {
+	spec, err := s.cachedSpec()
+	if err != nil {
+		return err
+	}
+
+	var body []byte
+	switch format {
+	case "json":
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, spec.json.body, "", "  "); err != nil {
+			return err
+		}
+		buf.WriteByte('\n')
+		body = buf.Bytes()
+	case "yaml":
+		body = spec.yaml.body
+	default:
+		return fmt.Errorf("unknown spec format %q", format)
+	}
+
+	_, err = w.Write(body)
+	return err
+}
+
+// OnSpec registers a function that post-processes the OpenAPI document after
+// it is generated and before it is served. Hooks run in the order they were
+// registered, each time the document is rebuilt, and must not register
+// endpoints or providers themselves.
+func (s *App) OnSpec(hook func(doc *openapi.Document)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.specHooks = append(s.specHooks, hook)
+	s.spec = nil
+}
+
+// Spec returns the OpenAPI document as served, after all OnSpec hooks ran.
+// The result is a copy that can be modified freely.
+func (s *App) Spec() (*openapi.Document, error) {
+	spec, err := s.cachedSpec()
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &openapi.Document{}
+	if err := json.Unmarshal(spec.json.body, doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// buildDocument generates the OpenAPI document from the registered endpoints.
+// The caller must hold s.mu.
+func (s *App) buildDocument() *openapi.Document {
+	// Copy what hooks could modify in place, so that they never touch the
+	// state the next build starts from.
+	doc := s.doc
+	doc.Paths = map[string]*openapi.PathItem{}
+	doc.Servers = append([]openapi.Server(nil), s.doc.Servers...)
+	doc.Tags = append([]openapi.Tag(nil), s.doc.Tags...)
+	doc.Security = append([]openapi.SecurityRequirement(nil), s.doc.Security...)
+	if s.doc.Info.Contact != nil {
+		contact := *s.doc.Info.Contact
+		doc.Info.Contact = &contact
+	}
+	if s.doc.Info.License != nil {
+		license := *s.doc.Info.License
+		doc.Info.License = &license
+	}
+	if s.doc.ExternalDocs != nil {
+		externalDocs := *s.doc.ExternalDocs
+		doc.ExternalDocs = &externalDocs
+	}
+
+	schemas := swagger.NewGenerator()
+	for _, e := range s.endpoints {
+		item, ok := doc.Paths[e.path]
+		if !ok {
+			item = &openapi.PathItem{}
+			doc.Paths[e.path] = item
+		}
+		if item.Operation(e.method) == nil {
+			item.SetOperation(e.method, s.buildOperation(schemas, e))
+		}
+	}
+
+	components := &openapi.Components{}
+	if len(schemas.Schemas()) > 0 {
+		components.Schemas = schemas.Schemas()
+	}
+	if len(s.securitySchemes) > 0 {
+		components.SecuritySchemes = map[string]*openapi.SecurityScheme{}
+		for name, scheme := range s.securitySchemes {
+			scheme := *scheme
+			components.SecuritySchemes[name] = &scheme
+		}
+	}
+	if components.Schemas != nil || components.SecuritySchemes != nil {
+		doc.Components = components
+	}
+
+	// Schemas are generated for 3.0 and converted when 3.1 is requested.
+	if strings.HasPrefix(doc.OpenAPI, "3.1.") {
+		doc.ConvertTo31()
+	}
+
+	return &doc
+}
+
+var parameterOrder = map[string]int{"path": 0, "query": 1, "header": 2, "cookie": 3}
+
+func (s *App) buildOperation(schemas *swagger.Generator, e *Endpoint) *openapi.Operation {
+	op := &openapi.Operation{
+		OperationID: e.operationID,
+		Summary:     e.summary,
+		Description: e.description,
+		Deprecated:  e.deprecated,
+		Tags:        append([]string{}, e.tags...),
+		Parameters:  []*openapi.Parameter{},
+		Responses:   map[string]*openapi.Response{},
+	}
+
+	if e.externalDocs != nil {
+		externalDocs := *e.externalDocs
+		op.ExternalDocs = &externalDocs
+	}
+	if e.security != nil {
+		security := append(openapi.SecurityRequirements{}, e.security...)
+		op.Security = &security
+	}
+
+	seen := map[reflect.Type]bool{}
+	for _, handler := range *e.handlerInstances {
+		schemas.UpdateOperationUsingParamTypes(op, s.requestParamTypes(handler.ParamTypes, seen))
+	}
+	// Parameters come from several structs; group them by location so that
+	// the order does not depend on how handlers split them up.
+	sort.SliceStable(op.Parameters, func(i, j int) bool {
+		return parameterOrder[op.Parameters[i].In] < parameterOrder[op.Parameters[j].In]
+	})
+
+	s.buildResponses(schemas, e, op)
+
+	return op
+}
+
+func (s *App) buildResponses(schemas *swagger.Generator, e *Endpoint, op *openapi.Operation) {
+	// Named variants by discriminator value, by status code.
+	mappings := map[string]map[string]*openapi.Schema{}
+
+	for _, responseType := range e.responseTypes {
+		code := fmt.Sprintf("%d", responseType.code)
+
+		response, ok := op.Responses[code]
+		if !ok {
+			response = &openapi.Response{Description: responseType.description}
+			op.Responses[code] = response
+		} else {
+			response.Description += " or " + responseType.description
+		}
+
+		for _, header := range responseType.headers {
+			if response.Headers == nil {
+				response.Headers = map[string]*openapi.Header{}
+			}
+			response.Headers[header.name] = &openapi.Header{
+				Description: header.description,
+				Schema:      schemas.GetSwaggerSchemaForType(reflect.TypeOf(header.value)),
+			}
+		}
+
+		if responseType.response == nil {
+			continue
+		}
+		t := reflect.TypeOf(responseType.response)
+
+		if fields, ok := reflection.GetResponseFields(t); ok {
+			for _, field := range fields.Headers {
+				if response.Headers == nil {
+					response.Headers = map[string]*openapi.Header{}
+				}
+				response.Headers[field.Tag.Get("header")] = schemas.ResponseHeader(field)
+			}
+			t = fields.Body.Type
+		}
+		schema := schemas.GetSwaggerSchemaForType(t)
+
+		if responseType.variant != "" && schema.Ref != "" {
+			if mappings[code] == nil {
+				mappings[code] = map[string]*openapi.Schema{}
+			}
+			mappings[code][responseType.variant] = schema
+		}
+
+		if response.Content == nil {
+			response.Content = map[string]*openapi.MediaType{}
+		}
+
+		media, ok := response.Content[responseType.contentType]
+		if !ok {
+			media = &openapi.MediaType{Schema: schema}
+			response.Content[responseType.contentType] = media
+		} else if media.Schema.OneOf != nil {
+			media.Schema.OneOf = append(media.Schema.OneOf, schema)
+		} else {
+			media.Schema = &openapi.Schema{
+				OneOf: []*openapi.Schema{media.Schema, schema},
+			}
+		}
+
+		for _, example := range responseType.examples {
+			if media.Examples == nil {
+				media.Examples = map[string]*openapi.Example{}
+			}
+			media.Examples[example.name] = &openapi.Example{
+				Summary: responseType.description,
+				Value:   example.value,
+			}
+		}
+	}
+
+	for status, property := range e.discriminators {
+		code := fmt.Sprintf("%d", status)
+		response, ok := op.Responses[code]
+		if !ok {
+			continue
+		}
+		for _, media := range response.Content {
+			if media.Schema.OneOf != nil {
+				media.Schema.Discriminator = schemas.Discriminator(property, mappings[code])
+			}
+		}
+	}
+}
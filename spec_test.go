@@ -0,0 +1,269 @@
+package simpleapi_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sattvikc/go-simpleapi"
+	"github.com/sattvikc/go-simpleapi/openapi"
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+type Author struct {
+	Name string `json:"name" example:"Ursula K. Le Guin"`
+}
+
+type Cover struct {
+	Checksum []byte `json:"checksum"`
+}
+
+type Novel struct {
+	ID       int64   `json:"id" readOnly:"true"`
+	Title    string  `json:"title" doc:"Title of the novel"`
+	Kind     string  `json:"kind" enum:"novel"`
+	Status   *string `json:"status" enum:"draft,published"`
+	Subtitle *string `json:"subtitle"`
+	Author   Author  `json:"author" doc:"Who wrote it"`
+	Editor   *Author `json:"editor"`
+	Cover    *Cover  `json:"cover,omitempty"`
+}
+
+type GetNovel struct {
+	ID   int64  `path:"id" doc:"Novel id"`
+	Lang string `query:"lang" enum:"en,fr" example:"en"`
+}
+
+type UploadCover struct {
+	Form struct {
+		Caption string         `form:"caption"`
+		Image   multipart.File `form:"image"`
+	} `body:"multipart"`
+}
+
+func goldenApp(opts ...simpleapi.Option) *simpleapi.App {
+	app := simpleapi.New(opts...)
+	app.Endpoint("/novels/{id}", func(e *simpleapi.Endpoint) interface{} {
+		e.WithOperationID("getNovel").
+			WithResponse(200, Novel{}, "The novel").
+			WithResponse(404, nil, "Not found").
+			GET()
+		return func(ctx *simpleapi.Context, req GetNovel) error {
+			return nil
+		}
+	})
+	app.Endpoint("/novels/{id}/cover", func(e *simpleapi.Endpoint) interface{} {
+		e.WithOperationID("uploadCover").
+			WithResponse(204, nil, "Uploaded").
+			PUT()
+		return func(ctx *simpleapi.Context, req UploadCover) error {
+			return nil
+		}
+	})
+	return app
+}
+
+func assertGolden(t *testing.T, name string, app *simpleapi.App) {
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	assert.Equal(t, 200, w.Code)
+
+	var actual bytes.Buffer
+	assert.NoError(t, json.Indent(&actual, w.Body.Bytes(), "", "  "))
+	actual.WriteString("\n")
+
+	path := filepath.Join("testdata", name)
+	if *update {
+		assert.NoError(t, os.WriteFile(path, actual.Bytes(), 0o644))
+	}
+
+	expected, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, string(expected), actual.String())
+}
+
+func TestGoldenOpenAPI30(t *testing.T) {
+	assertGolden(t, "openapi-3.0.json", goldenApp())
+}
+
+func TestGoldenOpenAPI31(t *testing.T) {
+	assertGolden(t, "openapi-3.1.json", goldenApp(simpleapi.WithOpenAPIVersion("3.1.0")))
+}
+
+func TestYAMLSpec(t *testing.T) {
+	app := goldenApp()
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.yaml", nil))
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, "application/yaml", w.Header().Get("Content-Type"))
+
+	var written bytes.Buffer
+	assert.NoError(t, app.WriteSpec(&written, "yaml"))
+	assert.Equal(t, w.Body.String(), written.String())
+	assert.Contains(t, written.String(), "openapi: 3.0.0\ninfo:\n  title: simpleapi\n")
+
+	written.Reset()
+	assert.NoError(t, app.WriteSpec(&written, "json"))
+	expected, err := os.ReadFile(filepath.Join("testdata", "openapi-3.0.json"))
+	assert.NoError(t, err)
+	assert.Equal(t, string(expected), written.String())
+
+	assert.Error(t, app.WriteSpec(&written, "xml"))
+}
+
+func TestParametersAreGroupedByLocation(t *testing.T) {
+	app := simpleapi.New()
+	app.Endpoint("/novels/{id}", func(e *simpleapi.Endpoint) interface{} {
+		e.GET()
+		return func(ctx *simpleapi.Context, req struct {
+			Trace string `header:"X-Trace"`
+			Lang  string `query:"lang"`
+			ID    int64  `path:"id"`
+		}) error {
+			return nil
+		}
+	})
+
+	doc, err := app.Spec()
+	assert.NoError(t, err)
+	names := []string{}
+	for _, parameter := range doc.Paths["/novels/{id}"].Get.Parameters {
+		names = append(names, parameter.Name)
+	}
+	assert.Equal(t, []string{"id", "lang", "X-Trace"}, names)
+}
+
+type Created struct {
+	Status string `json:"status"`
+	ID     int64  `json:"id"`
+}
+
+type Exists struct {
+	Status string `json:"status"`
+}
+
+func TestDiscriminatedResponses(t *testing.T) {
+	app := simpleapi.New()
+	app.Endpoint("/novels", func(e *simpleapi.Endpoint) interface{} {
+		e.WithResponse(200, Created{}, "Created",
+			simpleapi.ResponseVariant("CREATED"),
+			simpleapi.ResponseHeader("Location", "", "URL of the novel"),
+			simpleapi.ResponseHeader("ETag", nil, "Version of the novel"),
+			simpleapi.ResponseExample("created", Created{Status: "CREATED", ID: 1})).
+			WithResponse(200, Exists{}, "Exists", simpleapi.ResponseVariant("EXISTS")).
+			WithDiscriminator(200, "status").
+			POST()
+		return func(ctx *simpleapi.Context) error {
+			return nil
+		}
+	})
+
+	doc, err := app.Spec()
+	assert.NoError(t, err)
+	response := doc.Paths["/novels"].Post.Responses["200"]
+
+	assert.Equal(t, "URL of the novel", response.Headers["Location"].Description)
+	assert.Equal(t, openapi.Types{"string"}, response.Headers["Location"].Schema.Type)
+	assert.Equal(t, openapi.Types{"string"}, response.Headers["ETag"].Schema.Type)
+
+	media := response.Content["application/json"]
+	assert.Equal(t, []*openapi.Schema{openapi.RefTo("Created"), openapi.RefTo("Exists")}, media.Schema.OneOf)
+	assert.Equal(t, &openapi.Discriminator{
+		PropertyName: "status",
+		Mapping: map[string]string{
+			"CREATED": "#/components/schemas/Created",
+			"EXISTS":  "#/components/schemas/Exists",
+		},
+	}, media.Schema.Discriminator)
+	assert.Equal(t, map[string]interface{}{"status": "CREATED", "id": float64(1)}, media.Examples["created"].Value)
+	assert.Equal(t, "Created", media.Examples["created"].Summary)
+}
+
+type CreatedNovel struct {
+	Location  string    `header:"Location" doc:"URL of the novel"`
+	Remaining int       `header:"X-RateLimit-Remaining"`
+	ETag      *string   `header:"ETag"`
+	Modified  time.Time `header:"Last-Modified"`
+	Body      Created   `body:"json"`
+}
+
+func TestResponseStructHeaders(t *testing.T) {
+	app := simpleapi.New()
+	app.Endpoint("/novels", func(e *simpleapi.Endpoint) interface{} {
+		e.WithResponse(201, CreatedNovel{}, "Created").POST()
+		return func(ctx *simpleapi.Context) error {
+			return ctx.JSON(201, CreatedNovel{
+				Location:  "/novels/1",
+				Remaining: 0,
+				Modified:  time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+				Body:      Created{Status: "CREATED", ID: 1},
+			})
+		}
+	})
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/novels", nil))
+	assert.Equal(t, 201, w.Code)
+	assert.Equal(t, "/novels/1", w.Header().Get("Location"))
+	assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+	assert.Equal(t, "Tue, 02 Jan 2024 03:04:05 GMT", w.Header().Get("Last-Modified"))
+	assert.NotContains(t, w.Header(), "Etag")
+	assert.JSONEq(t, `{"status": "CREATED", "id": 1}`, w.Body.String())
+
+	doc, err := app.Spec()
+	assert.NoError(t, err)
+	response := doc.Paths["/novels"].Post.Responses["201"]
+	// Empty strings are not sent, so string headers are optional.
+	assert.Equal(t, &openapi.Header{
+		Description: "URL of the novel",
+		Schema:      &openapi.Schema{Type: openapi.Types{"string"}},
+	}, response.Headers["Location"])
+	assert.False(t, response.Headers["ETag"].Required)
+	assert.True(t, response.Headers["X-RateLimit-Remaining"].Required)
+	assert.Equal(t, openapi.Types{"integer"}, response.Headers["X-RateLimit-Remaining"].Schema.Type)
+	assert.Equal(t, &openapi.Header{
+		Required: true,
+		Schema: &openapi.Schema{
+			Type:        openapi.Types{"string"},
+			Description: "HTTP-date, e.g. Mon, 02 Jan 2006 15:04:05 GMT",
+		},
+	}, response.Headers["Last-Modified"])
+	assert.Equal(t, openapi.RefTo("Created"), response.Content["application/json"].Schema)
+	assert.NotContains(t, doc.Components.Schemas, "CreatedNovel")
+}
+
+func TestStructsWithoutBodyAreNotResponseStructs(t *testing.T) {
+	type credentials struct {
+		User  string `header:"X-User" json:"user"`
+		Token string `json:"token"`
+	}
+
+	app := simpleapi.New()
+	app.Endpoint("/credentials", func(e *simpleapi.Endpoint) interface{} {
+		e.WithResponse(200, credentials{}, "Credentials").GET()
+		return func(ctx *simpleapi.Context) error {
+			return ctx.JSON(200, credentials{User: "sattvik", Token: "abc"})
+		}
+	})
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/credentials", nil))
+	assert.Empty(t, w.Header().Get("X-User"))
+	assert.JSONEq(t, `{"user": "sattvik", "token": "abc"}`, w.Body.String())
+
+	doc, err := app.Spec()
+	assert.NoError(t, err)
+	response := doc.Paths["/credentials"].Get.Responses["200"]
+	assert.Empty(t, response.Headers)
+	assert.Equal(t, openapi.RefTo("credentials"), response.Content["application/json"].Schema)
+}
@@ -0,0 +1,148 @@
+package simpleapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SSEWriter streams Server-Sent Events to the client of a request.
+type SSEWriter struct {
+	ctx    *Context
+	mu     sync.Mutex
+	stop   chan struct{}
+	closed bool
+}
+
+// SSE switches the response to a text/event-stream and returns a writer for
+// sending events. The status code, usually the one declared with
+// Endpoint.WithEventStream, and the headers are written immediately.
+func (c *Context) SSE(code int) (*SSEWriter, error) {
+	if _, ok := c.Response.Unwrap().(http.Flusher); !ok {
+		return nil, errors.New("response writer does not support flushing")
+	}
+
+	header := c.Response.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no")
+	c.Response.WriteHeader(code)
+	c.Response.Flush()
+
+	return &SSEWriter{
+		ctx:  c,
+		stop: make(chan struct{}),
+	}, nil
+}
+
+// LastEventID returns the Last-Event-ID header sent by a reconnecting client.
+func (s *SSEWriter) LastEventID() string {
+	return s.ctx.Request.Header.Get("Last-Event-ID")
+}
+
+// Done is closed when the client disconnects.
+func (s *SSEWriter) Done() <-chan struct{} {
+	return s.ctx.Done()
+}
+
+// Send writes a single event. Strings and byte slices are sent as is, any
+// other data is encoded as JSON. Empty event and id are omitted.
+func (s *SSEWriter) Send(event, id string, data interface{}) error {
+	var payload string
+	switch d := data.(type) {
+	case string:
+		payload = d
+	case []byte:
+		payload = string(d)
+	default:
+		dataBytes, err := json.Marshal(data)
+		if err != nil {
+			return err
+		}
+		payload = string(dataBytes)
+	}
+
+	var b strings.Builder
+	if id != "" {
+		fmt.Fprintf(&b, "id: %s\n", stripNewlines(id))
+	}
+	if event != "" {
+		fmt.Fprintf(&b, "event: %s\n", stripNewlines(event))
+	}
+	for _, line := range strings.Split(strings.ReplaceAll(payload, "\r\n", "\n"), "\n") {
+		fmt.Fprintf(&b, "data: %s\n", line)
+	}
+	b.WriteString("\n")
+
+	return s.write(b.String())
+}
+
+// Retry tells the client how long to wait before reconnecting.
+func (s *SSEWriter) Retry(d time.Duration) error {
+	return s.write(fmt.Sprintf("retry: %d\n\n", d.Milliseconds()))
+}
+
+// Comment writes a comment line, which clients ignore.
+func (s *SSEWriter) Comment(text string) error {
+	return s.write(fmt.Sprintf(": %s\n\n", stripNewlines(text)))
+}
+
+// KeepAlive sends a comment every interval until the writer is closed or the
+// client disconnects, so that idle connections are not dropped by proxies.
+func (s *SSEWriter) KeepAlive(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := s.Comment("keep-alive"); err != nil {
+					return
+				}
+			case <-s.stop:
+				return
+			case <-s.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Close stops the keep-alive loop. Further writes return an error.
+func (s *SSEWriter) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.closed {
+		s.closed = true
+		close(s.stop)
+	}
+}
+
+func (s *SSEWriter) write(msg string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return errors.New("event stream is closed")
+	}
+	if err := s.ctx.Err(); err != nil {
+		return err
+	}
+
+	if _, err := s.ctx.Response.Write([]byte(msg)); err != nil {
+		return err
+	}
+	s.ctx.Response.Flush()
+	return nil
+}
+
+func stripNewlines(s string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(s)
+}
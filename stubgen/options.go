@@ -0,0 +1,117 @@
+package stubgen
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sattvikc/go-simpleapi/openapi"
+)
+
+// options writes an Options function returning the options that describe
+// the document as a whole: info, servers, tags and security. It returns notes
+// on what could not be generated.
+func (g *generator) options(buf *bytes.Buffer) []string {
+	notes := []string{}
+	options := []string{"simpleapi.WithInfo(" + literal(reflect.ValueOf(g.doc.Info)) + ")"}
+
+	if len(g.doc.Servers) > 0 {
+		servers := []string{}
+		for _, server := range g.doc.Servers {
+			servers = append(servers, literal(reflect.ValueOf(server)))
+		}
+		options = append(options, "simpleapi.WithServers("+strings.Join(servers, ", ")+")")
+	}
+	if len(g.doc.Tags) > 0 {
+		tags := []string{}
+		for _, tag := range g.doc.Tags {
+			tags = append(tags, literal(reflect.ValueOf(tag)))
+		}
+		options = append(options, "simpleapi.WithTags("+strings.Join(tags, ", ")+")")
+	}
+	if g.doc.ExternalDocs != nil {
+		options = append(options, "simpleapi.WithExternalDocs("+literal(reflect.ValueOf(*g.doc.ExternalDocs))+")")
+	}
+	if g.doc.Components != nil {
+		for _, name := range sortedKeys(g.doc.Components.SecuritySchemes) {
+			options = append(options, fmt.Sprintf("simpleapi.WithSecurityScheme(%q, %s)",
+				name, securityScheme(g.doc.Components.SecuritySchemes[name])))
+		}
+	}
+	for _, requirement := range g.doc.Security {
+		if len(requirement) != 1 {
+			notes = append(notes, fmt.Sprintf("security requirement {%s} of the document is skipped, simpleapi.WithSecurity requires one scheme at a time; declare it on the endpoints with WithSecurityRequirement.",
+				strings.Join(sortedKeys(requirement), ", ")))
+			continue
+		}
+		for name, scopes := range requirement {
+			options = append(options, fmt.Sprintf("simpleapi.WithSecurity(%s)", quoteAll(append([]string{name}, scopes...))))
+		}
+	}
+
+	buf.WriteString("\n// Options returns the options describing the API, to pass to simpleapi.New.\n")
+	buf.WriteString("func Options() []simpleapi.Option {\n\treturn []simpleapi.Option{\n")
+	for _, option := range options {
+		fmt.Fprintf(buf, "\t\t%s,\n", option)
+	}
+	buf.WriteString("\t}\n}\n")
+	return notes
+}
+
+// securityScheme returns the constructor call for a scheme, or a literal for
+// those without one.
+func securityScheme(scheme *openapi.SecurityScheme) string {
+	switch {
+	case scheme.Description != "":
+		// The constructors do not take a description.
+	case scheme.Type == "http" && strings.EqualFold(scheme.Scheme, "bearer"):
+		return fmt.Sprintf("simpleapi.HTTPBearer(%q)", scheme.BearerFormat)
+	case scheme.Type == "http" && strings.EqualFold(scheme.Scheme, "basic"):
+		return "simpleapi.HTTPBasic()"
+	case scheme.Type == "apiKey" && scheme.In == "header":
+		return fmt.Sprintf("simpleapi.APIKeyHeader(%q)", scheme.Name)
+	case scheme.Type == "apiKey" && scheme.In == "query":
+		return fmt.Sprintf("simpleapi.APIKeyQuery(%q)", scheme.Name)
+	case scheme.Type == "apiKey" && scheme.In == "cookie":
+		return fmt.Sprintf("simpleapi.APIKeyCookie(%q)", scheme.Name)
+	case scheme.Type == "openIdConnect":
+		return fmt.Sprintf("simpleapi.OpenIDConnect(%q)", scheme.OpenIDConnectURL)
+	}
+	return literal(reflect.ValueOf(*scheme))
+}
+
+// literal returns a Go expression for a value of the openapi model, whose
+// types simpleapi re-exports under the same names.
+func literal(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return "nil"
+		}
+		return "&" + literal(v.Elem())
+
+	case reflect.Struct:
+		fields := []string{}
+		for i := 0; i < v.NumField(); i++ {
+			if !v.Field(i).IsZero() {
+				fields = append(fields, v.Type().Field(i).Name+": "+literal(v.Field(i)))
+			}
+		}
+		return "simpleapi." + v.Type().Name() + "{" + strings.Join(fields, ", ") + "}"
+
+	case reflect.Map:
+		entries := []string{}
+		for _, key := range v.MapKeys() {
+			entries = append(entries, strconv.Quote(key.String())+": "+literal(v.MapIndex(key)))
+		}
+		sort.Strings(entries)
+		return "map[string]" + v.Type().Elem().Name() + "{" + strings.Join(entries, ", ") + "}"
+
+	case reflect.String:
+		return strconv.Quote(v.String())
+	}
+	return fmt.Sprint(v.Interface())
+}
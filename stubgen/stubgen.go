@@ -0,0 +1,502 @@
+// Package stubgen generates simpleapi server stubs from an OpenAPI 3
+// document: a Go type per component schema, a request struct per operation
+// with path, query, header and body tags, and a handler builder that
+// declares the documented responses and leaves the handler body to be
+// written.
+package stubgen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sattvikc/go-simpleapi/openapi"
+)
+
+// Config configures the generated package.
+type Config struct {
+	// Package is the name of the generated package.
+	Package string
+}
+
+// Generate returns the source of a package implementing doc with stubs.
+// Operations on methods that simpleapi cannot register, and parameters and
+// responses that cannot be expressed, are listed in the doc comment of the
+// generated code instead.
+func Generate(doc *openapi.Document, config Config) ([]byte, error) {
+	if !token.IsIdentifier(config.Package) {
+		return nil, fmt.Errorf("invalid package name %q", config.Package)
+	}
+
+	g := &generator{
+		doc:         doc,
+		imports:     map[string]bool{simpleapiPackage: true},
+		schemaNames: map[string]string{},
+		structs:     map[string]bool{},
+		names:       map[string]bool{"Options": true, "Register": true},
+	}
+
+	var options, types, builders, register bytes.Buffer
+	g.components(&types)
+	skipped := g.options(&options)
+	skipped = append(skipped, g.operations(&types, &builders, &register)...)
+	if g.nilable {
+		skipped = append(skipped, "arrays and maps become Go slices and maps, which are documented as nullable because nil ones are encoded as null.")
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Stubs generated by simpleapi stubgen from %s %s; implement the handlers.\n",
+		strconv.Quote(doc.Info.Title), doc.Info.Version)
+	for _, note := range skipped {
+		fmt.Fprintf(&buf, "//\n// Note: %s\n", note)
+	}
+	fmt.Fprintf(&buf, "\npackage %s\n\n", config.Package)
+	g.writeImports(&buf)
+	buf.Write(options.Bytes())
+	buf.WriteString("\n// Register registers the endpoints of the API with app.\nfunc Register(app *simpleapi.App) {\n")
+	buf.Write(register.Bytes())
+	buf.WriteString("}\n")
+	buf.Write(types.Bytes())
+	buf.Write(builders.Bytes())
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w", err)
+	}
+	return src, nil
+}
+
+const simpleapiPackage = "github.com/sattvikc/go-simpleapi"
+
+type generator struct {
+	doc     *openapi.Document
+	imports map[string]bool
+	// schemaNames maps component schema names to Go type names.
+	schemaNames map[string]string
+	// structs are the generated type names that are structs.
+	structs map[string]bool
+	names   map[string]bool
+	// nilable is set when a schema that is not nullable becomes a slice or
+	// a map, which simpleapi documents as nullable.
+	nilable bool
+}
+
+func (g *generator) writeImports(buf *bytes.Buffer) {
+	paths := make([]string, 0, len(g.imports))
+	for importPath := range g.imports {
+		if importPath != simpleapiPackage {
+			paths = append(paths, importPath)
+		}
+	}
+	sort.Strings(paths)
+
+	buf.WriteString("import (\n")
+	for _, importPath := range paths {
+		fmt.Fprintf(buf, "\t%q\n", importPath)
+	}
+	if len(paths) > 0 {
+		buf.WriteString("\n")
+	}
+	fmt.Fprintf(buf, "\t%q\n)\n", simpleapiPackage)
+}
+
+// components declares a type for each component schema.
+func (g *generator) components(buf *bytes.Buffer) {
+	if g.doc.Components == nil {
+		return
+	}
+
+	// Names are reserved first, as schemas reference each other.
+	names := sortedKeys(g.doc.Components.Schemas)
+	for _, name := range names {
+		g.schemaNames[name] = uniqueName(goName(name), g.names)
+	}
+
+	for _, name := range names {
+		schema := g.doc.Components.Schemas[name]
+		g.declare(buf, g.schemaNames[name], schema, schema.Description)
+	}
+}
+
+// declare writes a type declaration for schema.
+func (g *generator) declare(buf *bytes.Buffer, name string, schema *openapi.Schema, doc string) {
+	t, _ := g.baseType(schema, false)
+	if strings.HasPrefix(t, "struct {") {
+		g.structs[name] = true
+	}
+
+	buf.WriteString("\n")
+	writeComment(buf, doc)
+	fmt.Fprintf(buf, "type %s %s\n", name, t)
+}
+
+func writeComment(buf *bytes.Buffer, text string) {
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			fmt.Fprintf(buf, "// %s\n", line)
+		}
+	}
+}
+
+var methods = map[string]string{"get": "GET", "post": "POST", "put": "PUT", "delete": "DELETE"}
+
+// operations writes a request type and a builder per operation, in path
+// order, and returns notes on what could not be generated.
+func (g *generator) operations(types, builders, register *bytes.Buffer) []string {
+	notes := []string{}
+
+	for _, path := range sortedKeys(g.doc.Paths) {
+		item := g.doc.Paths[path]
+		for _, method := range item.Methods() {
+			op := item.Operation(method)
+			if _, ok := methods[method]; !ok {
+				notes = append(notes, fmt.Sprintf("%s %s is skipped, simpleapi does not register %s endpoints.",
+					strings.ToUpper(method), path, strings.ToUpper(method)))
+				continue
+			}
+
+			name := g.operationName(method, path, op)
+			opNotes := g.operation(types, builders, name, method, path, op)
+			for _, note := range opNotes {
+				notes = append(notes, fmt.Sprintf("%s %s: %s", strings.ToUpper(method), path, note))
+			}
+			fmt.Fprintf(register, "\tapp.Endpoint(%q, %s)\n", path, name)
+		}
+	}
+	return notes
+}
+
+// operationName names the builder after the operationId, or after the
+// method and path as simpleapi derives operation ids.
+func (g *generator) operationName(method, path string, op *openapi.Operation) string {
+	name := goName(op.OperationID)
+	if op.OperationID == "" {
+		name = goName(method + " " + strings.NewReplacer("{", "", "}", "").Replace(path))
+	}
+	return uniqueName(name, g.names)
+}
+
+func (g *generator) operation(types, builders *bytes.Buffer, name, method, path string, op *openapi.Operation) []string {
+	notes := []string{}
+
+	request, requestNotes := g.request(op)
+	notes = append(notes, requestNotes...)
+	requestName := ""
+	if request != "" {
+		requestName = uniqueName(name+"Request", g.names)
+		fmt.Fprintf(types, "\n// %s is the request of %s.\n", requestName, name)
+		fmt.Fprintf(types, "type %s %s\n", requestName, request)
+	}
+
+	var chain []string
+	if op.OperationID != "" {
+		chain = append(chain, fmt.Sprintf("WithOperationID(%q)", op.OperationID))
+	}
+	for _, tag := range op.Tags {
+		chain = append(chain, fmt.Sprintf("WithTag(%q)", tag))
+	}
+	if op.Summary != "" {
+		chain = append(chain, fmt.Sprintf("WithSummary(%q)", op.Summary))
+	}
+	if op.Description != "" {
+		chain = append(chain, fmt.Sprintf("WithDescription(%q)", op.Description))
+	}
+	if op.ExternalDocs != nil {
+		chain = append(chain, fmt.Sprintf("WithExternalDocs(%q, %q)", op.ExternalDocs.URL, op.ExternalDocs.Description))
+	}
+	if op.Deprecated {
+		chain = append(chain, "Deprecated()")
+	}
+	if op.Security != nil {
+		chain = append(chain, securityCalls(*op.Security)...)
+	}
+
+	responses, responseNotes := g.responses(types, name, op)
+	chain = append(chain, responses...)
+	notes = append(notes, responseNotes...)
+	chain = append(chain, methods[method]+"()")
+
+	if summary := strings.TrimSuffix(strings.TrimSpace(op.Summary), "."); summary != "" {
+		fmt.Fprintf(builders, "\n// %s handles %s %s: %s.\n", name, methods[method], path, summary)
+	} else {
+		fmt.Fprintf(builders, "\n// %s handles %s %s.\n", name, methods[method], path)
+	}
+	fmt.Fprintf(builders, "func %s(e *simpleapi.Endpoint) interface{} {\n", name)
+	fmt.Fprintf(builders, "\te.%s\n\n", strings.Join(chain, ".\n\t\t"))
+	if request != "" {
+		fmt.Fprintf(builders, "\treturn func(ctx *simpleapi.Context, req %s) error {\n", requestName)
+	} else {
+		builders.WriteString("\treturn func(ctx *simpleapi.Context) error {\n")
+	}
+	builders.WriteString("\t\treturn simpleapi.ErrNotImplemented\n\t}\n}\n")
+
+	return notes
+}
+
+// request returns the struct type of the request of op, or "" when it takes
+// no parameters and no body.
+func (g *generator) request(op *openapi.Operation) (string, []string) {
+	notes := []string{}
+	var fields strings.Builder
+	used := map[string]bool{}
+
+	for _, parameter := range op.Parameters {
+		if parameter.In == "cookie" {
+			notes = append(notes, fmt.Sprintf("cookie parameter %s is not bound by simpleapi.", parameter.Name))
+			continue
+		}
+
+		t := "string"
+		if parameter.Schema != nil {
+			t = g.goType(parameter.Schema, false)
+		}
+		// Optional parameters are pointers, so that absence can be told
+		// apart from the zero value.
+		if !parameter.Required && !strings.HasPrefix(t, "*") && !strings.HasPrefix(t, "[]") {
+			t = "*" + t
+		}
+
+		tags := []string{tag(parameter.In, parameter.Name)}
+		if parameter.Description != "" {
+			tags = append(tags, tag("doc", parameter.Description))
+		}
+		if parameter.Deprecated {
+			tags = append(tags, tag("deprecated", "true"))
+		}
+		if parameter.Schema != nil {
+			for _, schemaTag := range schemaTags(parameter.Schema) {
+				if !strings.HasPrefix(schemaTag, "doc:") && !strings.HasPrefix(schemaTag, "deprecated:") {
+					tags = append(tags, schemaTag)
+				}
+			}
+		}
+		fmt.Fprintf(&fields, "%s %s `%s`\n", uniqueName(goName(parameter.Name), used), t, strings.Join(tags, " "))
+	}
+
+	if body := op.RequestBody; body != nil {
+		if field, ok := g.body(body); ok {
+			used["Body"] = true
+			fields.WriteString(field)
+		} else {
+			notes = append(notes, fmt.Sprintf("request body of type %s is not bound by simpleapi.",
+				strings.Join(sortedKeys(body.Content), ", ")))
+		}
+	}
+
+	if fields.Len() == 0 {
+		return "", notes
+	}
+	return "struct {\n" + fields.String() + "}", notes
+}
+
+// body returns the Body field of a request, preferring JSON to forms.
+func (g *generator) body(body *openapi.RequestBody) (string, bool) {
+	for _, contentType := range sortedKeys(body.Content) {
+		if isJSON(contentType) {
+			return fmt.Sprintf("Body %s `body:\"json\"`\n", g.goType(body.Content[contentType].Schema, false)), true
+		}
+	}
+
+	for _, form := range []struct{ contentType, kind string }{
+		{"multipart/form-data", "multipart"},
+		{"application/x-www-form-urlencoded", "urlencoded"},
+	} {
+		kind := form.kind
+		media, ok := body.Content[form.contentType]
+		if !ok {
+			continue
+		}
+		schema := media.Schema
+		if schema != nil && schema.Ref != "" && g.doc.Components != nil {
+			schema = g.doc.Components.Schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]
+		}
+		if schema == nil {
+			continue
+		}
+
+		var fields strings.Builder
+		used := map[string]bool{}
+		for _, name := range sortedKeys(schema.Properties) {
+			property := schema.Properties[name]
+			tags := append([]string{tag("form", name)}, schemaTags(property)...)
+			fmt.Fprintf(&fields, "%s %s `%s`\n", uniqueName(goName(name), used), g.goType(property, kind == "multipart"), strings.Join(tags, " "))
+		}
+		return fmt.Sprintf("Body struct {\n%s} `body:%q`\n", fields.String(), kind), true
+	}
+	return "", false
+}
+
+func isJSON(contentType string) bool {
+	return contentType == "application/json" || strings.HasSuffix(contentType, "+json")
+}
+
+// responses returns the WithResponse and WithEventStream calls for the
+// responses of op, declaring named types for inline object schemas.
+func (g *generator) responses(types *bytes.Buffer, name string, op *openapi.Operation) ([]string, []string) {
+	calls := []string{}
+	notes := []string{}
+
+	codes := []int{}
+	for code := range op.Responses {
+		status, err := strconv.Atoi(code)
+		if err != nil || status < 100 || status > 599 {
+			notes = append(notes, fmt.Sprintf("%s response is skipped, simpleapi declares responses by status code.", code))
+			continue
+		}
+		codes = append(codes, status)
+	}
+	sort.Ints(codes)
+
+	for _, code := range codes {
+		response := op.Responses[strconv.Itoa(code)]
+		opts := g.responseHeaders(response)
+
+		media, method, ok := responseContent(response)
+		if !ok {
+			notes = append(notes, fmt.Sprintf("%d response of type %s is declared without a body.",
+				code, strings.Join(sortedKeys(response.Content), ", ")))
+		}
+		if media == nil || media.Schema == nil {
+			calls = append(calls, responseCall("WithResponse", code, "nil", response.Description, opts))
+			continue
+		}
+
+		// Alternatives that are all references become one response each,
+		// told apart by the discriminator if there is one.
+		if alternatives := media.Schema.OneOf; len(alternatives) > 1 && allRefs(alternatives) {
+			descriptions := strings.Split(response.Description, " or ")
+			variants := map[string]string{}
+			if d := media.Schema.Discriminator; d != nil {
+				for value, ref := range d.Mapping {
+					variants[ref] = value
+				}
+			}
+
+			for i, alternative := range alternatives {
+				description := response.Description
+				if len(descriptions) == len(alternatives) {
+					description = descriptions[i]
+				}
+				altOpts := opts
+				if i > 0 {
+					altOpts = nil
+				}
+				if variant, ok := variants[alternative.Ref]; ok {
+					altOpts = append([]string{fmt.Sprintf("simpleapi.ResponseVariant(%q)", variant)}, altOpts...)
+				}
+				calls = append(calls, responseCall(method, code, g.refName(alternative.Ref)+"{}", description, altOpts))
+			}
+			if d := media.Schema.Discriminator; d != nil {
+				calls = append(calls, fmt.Sprintf("WithDiscriminator(%d, %q)", code, d.PropertyName))
+			}
+			continue
+		}
+
+		t := g.goType(media.Schema, false)
+		if strings.HasPrefix(t, "struct {") {
+			typeName := uniqueName(fmt.Sprintf("%s%dResponse", name, code), g.names)
+			g.declare(types, typeName, media.Schema, fmt.Sprintf("%s is the %d response of %s.", typeName, code, name))
+			t = typeName
+		}
+		calls = append(calls, responseCall(method, code, g.zeroValue(t), response.Description, opts))
+	}
+	return calls, notes
+}
+
+// responseContent picks the JSON or event stream content of a response.
+// ok is false when the response has content of other types only.
+func responseContent(response *openapi.Response) (media *openapi.MediaType, method string, ok bool) {
+	for _, contentType := range sortedKeys(response.Content) {
+		if isJSON(contentType) {
+			return response.Content[contentType], "WithResponse", true
+		}
+	}
+	if media, found := response.Content["text/event-stream"]; found {
+		return media, "WithEventStream", true
+	}
+	return nil, "WithResponse", len(response.Content) == 0
+}
+
+func allRefs(schemas []*openapi.Schema) bool {
+	for _, schema := range schemas {
+		if schema.Ref == "" {
+			return false
+		}
+	}
+	return true
+}
+
+func (g *generator) responseHeaders(response *openapi.Response) []string {
+	opts := []string{}
+	for _, name := range sortedKeys(response.Headers) {
+		header := response.Headers[name]
+		value := `""`
+		if header.Schema != nil {
+			value = g.zeroValue(g.goType(header.Schema, false))
+		}
+		opts = append(opts, fmt.Sprintf("simpleapi.ResponseHeader(%q, %s, %q)", name, value, header.Description))
+	}
+	return opts
+}
+
+func responseCall(method string, code int, value, description string, opts []string) string {
+	args := append([]string{strconv.Itoa(code), value, strconv.Quote(description)}, opts...)
+	if len(opts) == 0 {
+		return fmt.Sprintf("%s(%s)", method, strings.Join(args, ", "))
+	}
+	return fmt.Sprintf("%s(%s,\n\t\t\t%s)", method, strings.Join(args[:3], ", "), strings.Join(opts, ",\n\t\t\t"))
+}
+
+// zeroValue returns an expression of type t for declaring a response, whose
+// schema simpleapi derives from the type of the value.
+func (g *generator) zeroValue(t string) string {
+	switch {
+	case g.structs[t], t == "time.Time", strings.HasPrefix(t, "[]"), strings.HasPrefix(t, "map["):
+		return t + "{}"
+	case t == "string":
+		return `""`
+	case t == "bool":
+		return "false"
+	case t == "int":
+		return "0"
+	default:
+		return "new(" + t + ")"
+	}
+}
+
+// securityCalls returns the calls declaring the security requirements of an
+// operation.
+func securityCalls(requirements openapi.SecurityRequirements) []string {
+	if len(requirements) == 0 {
+		return []string{"WithoutSecurity()"}
+	}
+
+	calls := []string{}
+	for _, requirement := range requirements {
+		if len(requirement) == 1 {
+			for name, scopes := range requirement {
+				calls = append(calls, fmt.Sprintf("WithSecurity(%s)", quoteAll(append([]string{name}, scopes...))))
+			}
+			continue
+		}
+
+		entries := []string{}
+		for _, name := range sortedKeys(requirement) {
+			entries = append(entries, fmt.Sprintf("%q: {%s}", name, quoteAll(requirement[name])))
+		}
+		calls = append(calls, fmt.Sprintf("WithSecurityRequirement(simpleapi.SecurityRequirement{%s})", strings.Join(entries, ", ")))
+	}
+	return calls
+}
+
+func quoteAll(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = strconv.Quote(value)
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,114 @@
+package stubgen_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/sattvikc/go-simpleapi"
+	"github.com/sattvikc/go-simpleapi/example/petstore"
+	"github.com/sattvikc/go-simpleapi/openapi"
+	"github.com/sattvikc/go-simpleapi/stubgen"
+	"github.com/stretchr/testify/assert"
+)
+
+func loadPetstore(t *testing.T) *openapi.Document {
+	data, err := os.ReadFile("../example/petstore/openapi.yaml")
+	assert.NoError(t, err)
+	doc, err := openapi.Load(data)
+	assert.NoError(t, err)
+	return doc
+}
+
+func TestExampleStubsAreUpToDate(t *testing.T) {
+	src, err := stubgen.Generate(loadPetstore(t), stubgen.Config{Package: "petstore"})
+	assert.NoError(t, err)
+
+	expected, err := os.ReadFile("../example/petstore/stubs.go")
+	assert.NoError(t, err)
+	assert.Equal(t, string(expected), string(src), "run go generate ./example/petstore")
+}
+
+// The stubs describe the same API as the document they were generated from,
+// except for what the notes at the top of the file list.
+func TestStubsRoundTrip(t *testing.T) {
+	app := simpleapi.New(petstore.Options()...)
+	petstore.Register(app)
+	doc, err := app.Spec()
+	assert.NoError(t, err)
+
+	changes := []string{}
+	for _, change := range openapi.Diff(loadPetstore(t), doc) {
+		changes = append(changes, change.String())
+	}
+	assert.Equal(t, []string{
+		"[breaking] GET /events response 200 text/event-stream $.attributes: may now be null",
+		"[breaking] GET /events response 200 text/event-stream $.tags: may now be null",
+		"[non-breaking] GET /pets cookie session: parameter removed",
+		"[breaking] GET /pets response 200 application/json $: may now be null",
+		"[breaking] GET /pets response 200 application/json $[].attributes: may now be null",
+		"[breaking] GET /pets response 200 application/json $[].tags: may now be null",
+		"[breaking] GET /pets response default: response removed",
+		"[breaking] GET /pets/{petId} response 404 text/plain: content type removed",
+		"[breaking] PATCH /pets/{petId}: operation removed",
+	}, changes)
+}
+
+func TestGenerate(t *testing.T) {
+	doc := &openapi.Document{
+		OpenAPI: "3.1.0",
+		Info:    openapi.Info{Title: "Novels", Version: "1.0.0"},
+		Paths: map[string]*openapi.PathItem{
+			"/novels/{novel_id}": {Get: &openapi.Operation{
+				Summary: "Get a novel.",
+				Parameters: []*openapi.Parameter{
+					{Name: "novel_id", In: "path", Required: true, Schema: &openapi.Schema{Type: openapi.Types{"string"}}},
+				},
+				Responses: map[string]*openapi.Response{
+					"200": {Description: "Found", Content: map[string]*openapi.MediaType{
+						"application/json": {Schema: &openapi.Schema{
+							Type: openapi.Types{"object"},
+							Properties: map[string]*openapi.Schema{
+								"title":     {Type: openapi.Types{"string"}},
+								"published": {Type: openapi.Types{"string", "null"}, Format: "date-time"},
+							},
+							Required: []string{"title"},
+						}},
+					}},
+				},
+			}},
+		},
+	}
+
+	src, err := stubgen.Generate(doc, stubgen.Config{Package: "novels"})
+	assert.NoError(t, err)
+
+	code := string(src)
+	assert.Contains(t, code, "type GetNovelsNovelIDRequest struct {\n\tNovelID string `path:\"novel_id\"`\n}")
+	assert.Contains(t, code, "type GetNovelsNovelID200Response struct {\n\tPublished *time.Time `json:\"published,omitempty\"`\n\tTitle     string     `json:\"title\"`\n}")
+	assert.Contains(t, code, "// GetNovelsNovelID handles GET /novels/{novel_id}: Get a novel.\n")
+	assert.Contains(t, code, "WithResponse(200, GetNovelsNovelID200Response{}, \"Found\")")
+	assert.Contains(t, code, "app.Endpoint(\"/novels/{novel_id}\", GetNovelsNovelID)")
+}
+
+func TestGenerateNotesCombinedDocumentSecurity(t *testing.T) {
+	doc := &openapi.Document{
+		OpenAPI: "3.0.3",
+		Info:    openapi.Info{Title: "Novels", Version: "1.0.0"},
+		Components: &openapi.Components{SecuritySchemes: map[string]*openapi.SecurityScheme{
+			"api_key": {Type: "apiKey", In: "header", Name: "X-API-Key"},
+			"bearer":  {Type: "http", Scheme: "bearer"},
+		}},
+		Security: openapi.SecurityRequirements{
+			{"api_key": {}, "bearer": {}},
+			{"bearer": {}},
+		},
+	}
+
+	src, err := stubgen.Generate(doc, stubgen.Config{Package: "novels"})
+	assert.NoError(t, err)
+
+	code := string(src)
+	assert.Contains(t, code, "// Note: security requirement {api_key, bearer} of the document is skipped, "+
+		"simpleapi.WithSecurity requires one scheme at a time; declare it on the endpoints with WithSecurityRequirement.\n")
+	assert.Contains(t, code, "simpleapi.WithSecurity(\"bearer\"),\n")
+}
@@ -0,0 +1,307 @@
+package stubgen
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/sattvikc/go-simpleapi/openapi"
+)
+
+// goType returns the Go type for a schema. In forms, binary strings are
+// uploaded files.
+func (g *generator) goType(s *openapi.Schema, form bool) string {
+	t, nullable := g.baseType(s, form)
+	if nullable && !strings.HasPrefix(t, "[]") && !strings.HasPrefix(t, "map[") &&
+		t != "interface{}" && t != "multipart.File" {
+		return "*" + t
+	}
+	return t
+}
+
+func (g *generator) baseType(s *openapi.Schema, form bool) (string, bool) {
+	if s == nil {
+		return "interface{}", false
+	}
+	if s.Ref != "" {
+		return g.refName(s.Ref), s.Nullable
+	}
+
+	if len(s.AllOf) == 1 {
+		t, nullable := g.baseType(s.AllOf[0], form)
+		return t, nullable || s.Nullable
+	}
+	if len(s.AllOf) > 1 {
+		return g.structType(s), s.Nullable
+	}
+
+	// A null alternative, as written by 3.1 documents, makes the other one
+	// nullable.
+	for _, alternatives := range [][]*openapi.Schema{s.OneOf, s.AnyOf} {
+		if len(alternatives) == 0 {
+			continue
+		}
+		rest := []*openapi.Schema{}
+		for _, alternative := range alternatives {
+			if !isNull(alternative) {
+				rest = append(rest, alternative)
+			}
+		}
+		if len(rest) == 1 {
+			t, _ := g.baseType(rest[0], form)
+			return t, true
+		}
+		return "interface{}", false
+	}
+
+	nullable := s.Nullable
+	types := []string{}
+	for _, typ := range s.Type {
+		if typ == "null" {
+			nullable = true
+		} else {
+			types = append(types, typ)
+		}
+	}
+	if len(types) == 0 && s.Properties != nil {
+		types = []string{"object"}
+	}
+	if len(types) != 1 {
+		return "interface{}", false
+	}
+
+	switch types[0] {
+	case "string":
+		switch s.Format {
+		case "date-time":
+			g.imports["time"] = true
+			return "time.Time", nullable
+		case "binary":
+			if form {
+				g.imports["mime/multipart"] = true
+				return "multipart.File", false
+			}
+			g.nilable = g.nilable || !nullable
+			return "[]byte", nullable
+		case "byte":
+			g.nilable = g.nilable || !nullable
+			return "[]byte", nullable
+		}
+		return "string", nullable
+	case "integer":
+		switch s.Format {
+		case "int32":
+			return "int32", nullable
+		case "int64":
+			return "int64", nullable
+		}
+		return "int", nullable
+	case "number":
+		if s.Format == "float" {
+			return "float32", nullable
+		}
+		return "float64", nullable
+	case "boolean":
+		return "bool", nullable
+	case "array":
+		g.nilable = g.nilable || !nullable
+		return "[]" + g.goType(s.Items, form), nullable
+	case "object":
+		if len(s.Properties) > 0 {
+			return g.structType(s), nullable
+		}
+		g.nilable = g.nilable || !nullable
+		if s.AdditionalProperties != nil {
+			return "map[string]" + g.goType(s.AdditionalProperties, false), nullable
+		}
+		return "map[string]interface{}", nullable
+	}
+	return "interface{}", false
+}
+
+func isNull(s *openapi.Schema) bool {
+	return len(s.Type) == 1 && s.Type[0] == "null"
+}
+
+// structType returns a struct literal type for an object schema. The
+// schemas of allOf are embedded when they are references, and merged
+// otherwise.
+func (g *generator) structType(s *openapi.Schema) string {
+	var b strings.Builder
+	b.WriteString("struct {\n")
+
+	used := map[string]bool{}
+	var write func(s *openapi.Schema)
+	write = func(s *openapi.Schema) {
+		for _, part := range s.AllOf {
+			if part.Ref != "" {
+				name := g.refName(part.Ref)
+				used[name] = true
+				b.WriteString(name + "\n")
+			} else {
+				write(part)
+			}
+		}
+
+		for _, property := range sortedKeys(s.Properties) {
+			schema := s.Properties[property]
+			name := uniqueName(goName(property), used)
+			tags := []string{tag("json", property+omitEmpty(s, property))}
+			tags = append(tags, schemaTags(schema)...)
+			fmt.Fprintf(&b, "%s %s `%s`\n", name, g.goType(schema, false), strings.Join(tags, " "))
+		}
+	}
+	write(s)
+
+	b.WriteString("}")
+	return b.String()
+}
+
+func omitEmpty(s *openapi.Schema, property string) string {
+	for _, required := range s.Required {
+		if required == property {
+			return ""
+		}
+	}
+	return ",omitempty"
+}
+
+// schemaTags returns the documentation tags that the swagger package reads
+// back into the schema.
+func schemaTags(s *openapi.Schema) []string {
+	tags := []string{}
+	if s == nil {
+		return tags
+	}
+	if s.Description != "" {
+		tags = append(tags, tag("doc", s.Description))
+	}
+	if s.Example != nil {
+		if value, ok := scalar(s.Example); ok {
+			tags = append(tags, tag("example", value))
+		}
+	}
+	switch s.Format {
+	case "", "int32", "int64", "float", "double", "date-time", "binary", "byte":
+		// Implied by the Go type.
+	default:
+		tags = append(tags, tag("format", s.Format))
+	}
+	if len(s.Enum) > 0 {
+		values := []string{}
+		for _, value := range s.Enum {
+			if v, ok := scalar(value); ok && !strings.Contains(v, ",") {
+				values = append(values, v)
+			}
+		}
+		if len(values) == len(s.Enum) {
+			tags = append(tags, tag("enum", strings.Join(values, ",")))
+		}
+	}
+	if s.Deprecated {
+		tags = append(tags, tag("deprecated", "true"))
+	}
+	if s.ReadOnly {
+		tags = append(tags, tag("readOnly", "true"))
+	}
+	if s.WriteOnly {
+		tags = append(tags, tag("writeOnly", "true"))
+	}
+	return tags
+}
+
+func scalar(v interface{}) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case bool:
+		return strconv.FormatBool(v), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case int:
+		return strconv.Itoa(v), true
+	}
+	return "", false
+}
+
+// tag formats one key of a struct tag. Backquotes cannot appear in a raw
+// string, so they are dropped.
+func tag(key, value string) string {
+	return key + ":" + strconv.Quote(strings.ReplaceAll(value, "`", "'"))
+}
+
+// refName returns the Go type name of a component schema reference.
+func (g *generator) refName(ref string) string {
+	name := strings.TrimPrefix(ref, "#/components/schemas/")
+	if goName, ok := g.schemaNames[name]; ok {
+		return goName
+	}
+	return "interface{}"
+}
+
+var initialisms = map[string]string{
+	"api": "API", "http": "HTTP", "id": "ID", "ip": "IP", "json": "JSON",
+	"uri": "URI", "url": "URL", "uuid": "UUID",
+}
+
+// goName turns a name from the document, such as book_id or x-request-id,
+// into an exported Go identifier.
+func goName(name string) string {
+	var b strings.Builder
+	for _, part := range words(name) {
+		if initialism, ok := initialisms[strings.ToLower(part)]; ok {
+			b.WriteString(initialism)
+			continue
+		}
+		runes := []rune(part)
+		b.WriteString(string(unicode.ToUpper(runes[0])) + string(runes[1:]))
+	}
+
+	s := b.String()
+	if s == "" {
+		return "Field"
+	}
+	if unicode.IsDigit([]rune(s)[0]) {
+		return "N" + s
+	}
+	return s
+}
+
+// words splits a name at punctuation and at lower to upper case changes.
+func words(name string) []string {
+	words := []string{}
+	for _, field := range strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		runes := []rune(field)
+		start := 0
+		for i := 1; i < len(runes); i++ {
+			if unicode.IsLower(runes[i-1]) && unicode.IsUpper(runes[i]) {
+				words = append(words, string(runes[start:i]))
+				start = i
+			}
+		}
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
+
+func uniqueName(name string, used map[string]bool) string {
+	candidate := name
+	for i := 2; used[candidate]; i++ {
+		candidate = fmt.Sprintf("%s%d", name, i)
+	}
+	used[candidate] = true
+	return candidate
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -1,47 +1,348 @@
 package simpleapi
 
 import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io/fs"
 	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sattvikc/go-simpleapi/ui"
 )
 
-const swaggerUI = `<!DOCTYPE html>
+var swaggerUI = template.Must(template.New("swagger-ui").Parse(`<!DOCTYPE html>
 <html>
 <head>
-<link type="text/css" rel="stylesheet" href="https://cdn.jsdelivr.net/npm/swagger-ui-dist@4/swagger-ui.css">
-<link rel="stylesheet" href="https://cdn.jsdelivr.net/npm/swagger-ui-themes@3.0.1/themes/3.x/theme-muted.min.css" />
-<link rel="shortcut icon" href="https://fastapi.tiangolo.com/img/favicon.png">
-<title>Swagger UI</title>
+<link type="text/css" rel="stylesheet" href="{{.CSS}}">
+{{if .Theme}}<link rel="stylesheet" href="{{.Theme}}" />
+{{end}}{{if .Favicon}}<link rel="shortcut icon" href="{{.Favicon}}">
+{{end}}<title>{{.Title}}</title>
 </head>
 <body>
 <div id="swagger-ui">
 </div>
-<script src="https://cdn.jsdelivr.net/npm/swagger-ui-dist@4/swagger-ui-bundle.js"></script>
+<script src="{{.Bundle}}"></script>
 <!-- SwaggerUIBundle is now available on the page -->
 <script>
-const ui = SwaggerUIBundle({
-	url: '/openapi.json',
-"dom_id": "#swagger-ui",
-"layout": "BaseLayout",
-"deepLinking": true,
-"showExtensions": true,
-"showCommonExtensions": true,
-oauth2RedirectUrl: window.location.origin + '/docs/oauth2-redirect',
+const ui = SwaggerUIBundle(Object.assign({{.Parameters}}, {
+url: {{.SpecURL}},
+oauth2RedirectUrl: window.location.origin + {{.OAuth2RedirectURL}},
 presets: [
 	SwaggerUIBundle.presets.apis,
 	SwaggerUIBundle.SwaggerUIStandalonePreset
 	],
-})
+}))
+</script>
+</body>
+</html>
+`))
+
+var redoc = template.Must(template.New("redoc").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8"/>
+<meta name="viewport" content="width=device-width, initial-scale=1">
+{{if .Favicon}}<link rel="shortcut icon" href="{{.Favicon}}">
+{{end}}<title>{{.Title}}</title>
+<style>
+body {
+	margin: 0;
+	padding: 0;
+}
+</style>
+</head>
+<body>
+<redoc spec-url="{{.SpecURL}}"></redoc>
+<script src="{{.Bundle}}"></script>
+</body>
+</html>
+`))
+
+// swaggerUIOAuth2Redirect is the page Swagger UI's OAuth2 flows redirect back
+// to. It hands the result of the authorization to the opener window.
+const swaggerUIOAuth2Redirect = `<!doctype html>
+<html lang="en-US">
+<head>
+<title>Swagger UI: OAuth2 Redirect</title>
+</head>
+<body>
+<script>
+'use strict';
+function run () {
+	var oauth2 = window.opener.swaggerUIRedirectOauth2;
+	var sentState = oauth2.state;
+	var redirectUrl = oauth2.redirectUrl;
+	var isValid, qp, arr;
+
+	if (/code|token|error/.test(window.location.hash)) {
+		qp = window.location.hash.substring(1).replace('?', '&');
+	} else {
+		qp = location.search.substring(1);
+	}
+
+	arr = qp.split("&");
+	arr.forEach(function (v, i, _arr) { _arr[i] = '"' + v.replace('=', '":"') + '"'; });
+	qp = qp ? JSON.parse('{' + arr.join() + '}',
+		function (key, value) {
+			return key === "" ? value : decodeURIComponent(value);
+		}
+	) : {};
+
+	isValid = qp.state === sentState;
+
+	if ((
+		oauth2.auth.schema.get("flow") === "accessCode" ||
+		oauth2.auth.schema.get("flow") === "authorizationCode" ||
+		oauth2.auth.schema.get("flow") === "authorization_code"
+	) && !oauth2.auth.code) {
+		if (!isValid) {
+			oauth2.errCb({
+				authId: oauth2.auth.name,
+				source: "auth",
+				level: "warning",
+				message: "Authorization may be unsafe, passed state was changed in server. The passed state wasn't returned from auth server."
+			});
+		}
+
+		if (qp.code) {
+			delete oauth2.state;
+			oauth2.auth.code = qp.code;
+			oauth2.callback({auth: oauth2.auth, redirectUrl: redirectUrl});
+		} else {
+			var oauthErrorMsg;
+			if (qp.error) {
+				oauthErrorMsg = "[" + qp.error + "]: " +
+					(qp.error_description ? qp.error_description + ". " : "no accessCode received from the server. ") +
+					(qp.error_uri ? "More info: " + qp.error_uri : "");
+			}
+
+			oauth2.errCb({
+				authId: oauth2.auth.name,
+				source: "auth",
+				level: "error",
+				message: oauthErrorMsg || "[Authorization failed]: no accessCode received from the server."
+			});
+		}
+	} else {
+		oauth2.callback({auth: oauth2.auth, token: qp, isValid: isValid, redirectUrl: redirectUrl});
+	}
+	window.close();
+}
+
+if (document.readyState !== 'loading') {
+	run();
+} else {
+	document.addEventListener('DOMContentLoaded', function () {
+		run();
+	});
+}
 </script>
 </body>
 </html>
 `
 
+type docsConfig struct {
+	specPath            string
+	swaggerUIPath       string
+	redocPath           string
+	swaggerUIParameters map[string]interface{}
+	assets              fs.FS
+	cdn                 bool
+}
+
+func defaultDocsConfig() docsConfig {
+	return docsConfig{
+		specPath:      "/openapi.json",
+		swaggerUIPath: "/docs",
+		swaggerUIParameters: map[string]interface{}{
+			"dom_id":               "#swagger-ui",
+			"layout":               "BaseLayout",
+			"deepLinking":          true,
+			"showExtensions":       true,
+			"showCommonExtensions": true,
+		},
+		assets: ui.Assets,
+	}
+}
+
+// WithOpenAPIPath sets where the OpenAPI document is served. Its YAML encoding
+// is served at the same path with a .yaml extension. An empty path disables
+// both, along with the documentation pages.
+func WithOpenAPIPath(path string) Option {
+	return func(s *App) {
+		s.docs.specPath = path
+	}
+}
+
+// WithSwaggerUI sets where Swagger UI is served. An empty path disables it.
+func WithSwaggerUI(path string) Option {
+	return func(s *App) {
+		s.docs.swaggerUIPath = path
+	}
+}
+
+// WithSwaggerUIParameters sets configuration parameters of Swagger UI, e.g.
+// "docExpansion" or "persistAuthorization". They are merged over the defaults.
+func WithSwaggerUIParameters(parameters map[string]interface{}) Option {
+	return func(s *App) {
+		for k, v := range parameters {
+			s.docs.swaggerUIParameters[k] = v
+		}
+	}
+}
+
+// WithReDoc serves a ReDoc page at path. It is disabled by default. The ReDoc
+// bundle is not embedded, so it needs WithDocsAssets or WithDocsCDN.
+func WithReDoc(path string) Option {
+	return func(s *App) {
+		s.docs.redocPath = path
+	}
+}
+
+// WithDocsAssets replaces the embedded Swagger UI and ReDoc assets. Files are
+// looked up by the names in package ui, e.g. swagger-ui-bundle.js.
+func WithDocsAssets(fsys fs.FS) Option {
+	return func(s *App) {
+		s.docs.assets = fsys
+	}
+}
+
+// WithDocsCDN loads the documentation assets that are not available locally
+// from jsDelivr, e.g. the ReDoc bundle, which is not embedded. Without it the
+// pages only reference assets served by the App, so that they work offline
+// and browsers make no requests elsewhere.
+func WithDocsCDN() Option {
+	return func(s *App) {
+		s.docs.cdn = true
+	}
+}
+
+// yamlSpecPath returns where the YAML encoding of the OpenAPI document is
+// served, next to the JSON one.
+func (c docsConfig) yamlSpecPath() string {
+	return strings.TrimSuffix(c.specPath, ".json") + ".yaml"
+}
+
+// assetsPath returns the path the documentation assets are served under.
+func (c docsConfig) assetsPath() string {
+	prefix := c.swaggerUIPath
+	if prefix == "" {
+		prefix = c.redocPath
+	}
+	return strings.TrimSuffix(prefix, "/") + "/assets"
+}
+
+// assetURL returns the URL of a documentation asset, or "" when it is not
+// available locally and the CDN is not enabled.
+func (c docsConfig) assetURL(asset ui.Asset) string {
+	if c.assets != nil && ui.Has(c.assets, asset.Name) {
+		return c.assetsPath() + "/" + asset.Name
+	}
+	if c.cdn {
+		return asset.CDN
+	}
+	return ""
+}
+
+// requireAssets panics when an asset a page cannot work without is missing.
+func (c docsConfig) requireAssets(assets ...ui.Asset) {
+	for _, asset := range assets {
+		if c.assetURL(asset) == "" {
+			panic(fmt.Sprintf("documentation asset %s is missing; provide it with WithDocsAssets or enable WithDocsCDN", asset.Name))
+		}
+	}
+}
+
+func (c docsConfig) title(app *App) string {
+	return app.doc.Info.Title
+}
+
 func addSwaggerRoutes(app *App) {
-	app.AddHandler("/docs", http.MethodGet, func(ctx *Context) error {
-		return ctx.HTML(200, swaggerUI)
+	docs := app.docs
+	if docs.specPath == "" {
+		return
+	}
+
+	if docs.swaggerUIPath != "" || docs.redocPath != "" {
+		app.AddHandler(docs.assetsPath()+"/{file}", http.MethodGet, func(ctx *Context, req struct {
+			File string `path:"file"`
+		}) error {
+			// Only the assets the pages use are served, not whatever else
+			// is in the file system.
+			known := false
+			for _, asset := range ui.All {
+				known = known || asset.Name == req.File
+			}
+			if !known {
+				return NewHTTPError(http.StatusNotFound, nil)
+			}
+			data, err := fs.ReadFile(docs.assets, req.File)
+			if err != nil {
+				return NewHTTPError(http.StatusNotFound, err)
+			}
+			ctx.Response.Header().Set("Cache-Control", "public, max-age=86400")
+			http.ServeContent(ctx.Response, ctx.Request, req.File, time.Time{}, bytes.NewReader(data))
+			return nil
+		})
+	}
+
+	if docs.swaggerUIPath != "" {
+		docs.requireAssets(ui.SwaggerUICSS, ui.SwaggerUIBundle)
+		app.AddHandler(docs.swaggerUIPath, http.MethodGet, func(ctx *Context) error {
+			var page bytes.Buffer
+			err := swaggerUI.Execute(&page, map[string]interface{}{
+				"Title":             docs.title(app) + " - Swagger UI",
+				"CSS":               docs.assetURL(ui.SwaggerUICSS),
+				"Theme":             docs.assetURL(ui.SwaggerUITheme),
+				"Favicon":           docs.assetURL(ui.Favicon),
+				"Bundle":            docs.assetURL(ui.SwaggerUIBundle),
+				"Parameters":        docs.swaggerUIParameters,
+				"SpecURL":           docs.specPath,
+				"OAuth2RedirectURL": strings.TrimSuffix(docs.swaggerUIPath, "/") + "/oauth2-redirect",
+			})
+			if err != nil {
+				return err
+			}
+			return ctx.HTML(200, page.String())
+		})
+
+		app.AddHandler(strings.TrimSuffix(docs.swaggerUIPath, "/")+"/oauth2-redirect", http.MethodGet, func(ctx *Context) error {
+			return ctx.HTML(200, swaggerUIOAuth2Redirect)
+		})
+	}
+
+	if docs.redocPath != "" {
+		docs.requireAssets(ui.ReDocBundle)
+		app.AddHandler(docs.redocPath, http.MethodGet, func(ctx *Context) error {
+			var page bytes.Buffer
+			err := redoc.Execute(&page, map[string]interface{}{
+				"Title":   docs.title(app) + " - ReDoc",
+				"Favicon": docs.assetURL(ui.Favicon),
+				"Bundle":  docs.assetURL(ui.ReDocBundle),
+				"SpecURL": docs.specPath,
+			})
+			if err != nil {
+				return err
+			}
+			return ctx.HTML(200, page.String())
+		})
+	}
+
+	app.AddHandler(docs.specPath, http.MethodGet, func(ctx *Context) error {
+		spec, err := app.cachedSpec()
+		if err != nil {
+			return err
+		}
+		return spec.json.serve(ctx)
 	})
 
-	app.AddHandler("/openapi.json", http.MethodGet, func(ctx *Context) error {
-		return ctx.JSON(200, app.swaggerJson)
+	app.AddHandler(docs.yamlSpecPath(), http.MethodGet, func(ctx *Context) error {
+		spec, err := app.cachedSpec()
+		if err != nil {
+			return err
+		}
+		return spec.yaml.serve(ctx)
 	})
 }
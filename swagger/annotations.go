@@ -0,0 +1,126 @@
+package swagger
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/sattvikc/go-simpleapi/openapi"
+)
+
+// annotateSchema applies the documentation tags of a struct field to the
+// schema of its value.
+func annotateSchema(schema *openapi.Schema, tag reflect.StructTag) *openapi.Schema {
+	annotations := &openapi.Schema{}
+
+	if doc := tag.Get("doc"); doc != "" {
+		annotations.Description = doc
+	}
+	if example, ok := tag.Lookup("example"); ok {
+		annotations.Example = parseTagValue(schema, example)
+	}
+	if format := tag.Get("format"); format != "" {
+		annotations.Format = format
+	}
+	if enum := tag.Get("enum"); enum != "" {
+		annotations.Enum = parseEnum(schema, enum)
+	}
+	annotations.Deprecated = tagFlag(tag, "deprecated")
+	annotations.ReadOnly = tagFlag(tag, "readOnly")
+	annotations.WriteOnly = tagFlag(tag, "writeOnly")
+
+	if reflect.ValueOf(*annotations).IsZero() {
+		return schema
+	}
+
+	if schema.Ref != "" {
+		// Siblings of $ref are ignored in OpenAPI 3.0.
+		annotations.AllOf = []*openapi.Schema{schema}
+		return annotations
+	}
+
+	if annotations.Description != "" {
+		schema.Description = annotations.Description
+	}
+	if annotations.Example != nil {
+		schema.Example = annotations.Example
+	}
+	if annotations.Format != "" {
+		schema.Format = annotations.Format
+	}
+	if annotations.Enum != nil {
+		schema.Enum = annotations.Enum
+	}
+	schema.Deprecated = schema.Deprecated || annotations.Deprecated
+	schema.ReadOnly = schema.ReadOnly || annotations.ReadOnly
+	schema.WriteOnly = schema.WriteOnly || annotations.WriteOnly
+	return schema
+}
+
+func tagFlag(tag reflect.StructTag, name string) bool {
+	value, err := strconv.ParseBool(tag.Get(name))
+	return err == nil && value
+}
+
+// annotateParameter applies the documentation tags of a request field to a
+// parameter object and its schema.
+func annotateParameter(parameter *openapi.Parameter, tag reflect.StructTag) {
+	schema := parameter.Schema
+
+	if doc := tag.Get("doc"); doc != "" {
+		parameter.Description = doc
+	}
+	if example, ok := tag.Lookup("example"); ok {
+		parameter.Example = parseTagValue(schema, example)
+	}
+	if tagFlag(tag, "deprecated") {
+		parameter.Deprecated = true
+	}
+	if format := tag.Get("format"); format != "" {
+		schema.Format = format
+	}
+	if enum := tag.Get("enum"); enum != "" {
+		schema.Enum = parseEnum(schema, enum)
+	}
+}
+
+func parseEnum(schema *openapi.Schema, enum string) []interface{} {
+	target := schema
+	if schema.Type.Is("array") && schema.Items != nil {
+		target = schema.Items
+	}
+
+	values := []interface{}{}
+	for _, value := range strings.Split(enum, ",") {
+		values = append(values, parseTagValue(target, strings.TrimSpace(value)))
+	}
+	return values
+}
+
+// parseTagValue converts a tag value to the JSON type of the schema it
+// describes, falling back to the raw string.
+func parseTagValue(schema *openapi.Schema, value string) interface{} {
+	switch {
+	case schema.Type.Is("integer"):
+		if v, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return v
+		}
+	case schema.Type.Is("number"):
+		if v, err := strconv.ParseFloat(value, 64); err == nil {
+			return v
+		}
+	case schema.Type.Is("boolean"):
+		if v, err := strconv.ParseBool(value); err == nil {
+			return v
+		}
+	case schema.Type.Is("string"):
+		return value
+	}
+
+	var v interface{}
+	if err := json.Unmarshal([]byte(value), &v); err == nil {
+		return v
+	}
+	return value
+}
@@ -0,0 +1,179 @@
+package swagger
+
+import (
+	"path"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/sattvikc/go-simpleapi/openapi"
+)
+
+// Generator builds OpenAPI schemas from Go types. Named struct types are
+// registered once under components/schemas and referenced with $ref, while
+// anonymous structs are inlined.
+type Generator struct {
+	schemas map[string]*openapi.Schema
+	names   map[reflect.Type]string
+	// types holds the registered types by their unqualified name.
+	types map[string][]reflect.Type
+	// refs holds the references made to each type, which are updated when
+	// the type is renamed.
+	refs           map[reflect.Type][]*openapi.Schema
+	discriminators []discriminator
+}
+
+type discriminator struct {
+	*openapi.Discriminator
+	variants map[string]*openapi.Schema
+}
+
+func NewGenerator() *Generator {
+	return &Generator{
+		schemas: map[string]*openapi.Schema{},
+		names:   map[reflect.Type]string{},
+		types:   map[string][]reflect.Type{},
+		refs:    map[reflect.Type][]*openapi.Schema{},
+	}
+}
+
+// Schemas returns the component schemas registered so far, keyed by name.
+func (g *Generator) Schemas() map[string]*openapi.Schema {
+	return g.schemas
+}
+
+// SchemaName returns the component name registered for t, if any.
+func (g *Generator) SchemaName(t reflect.Type) (string, bool) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	name, ok := g.names[t]
+	return name, ok
+}
+
+// Discriminator returns a discriminator whose mapping points to the given
+// variant references, and keeps it up to date when they are renamed.
+func (g *Generator) Discriminator(property string, variants map[string]*openapi.Schema) *openapi.Discriminator {
+	d := discriminator{
+		Discriminator: &openapi.Discriminator{PropertyName: property},
+		variants:      variants,
+	}
+	d.update()
+	g.discriminators = append(g.discriminators, d)
+	return d.Discriminator
+}
+
+func (d discriminator) update() {
+	if len(d.variants) == 0 {
+		d.Mapping = nil
+		return
+	}
+	d.Mapping = map[string]string{}
+	for name, variant := range d.variants {
+		d.Mapping[name] = variant.Ref
+	}
+}
+
+func (g *Generator) ref(t reflect.Type) *openapi.Schema {
+	if _, ok := g.names[t]; !ok {
+		base := sanitizeName(typeName(t))
+		g.types[base] = append(g.types[base], t)
+		g.nameTypes(base)
+
+		// Reserve the name before building the schema so that recursive
+		// types refer back to it instead of recursing forever.
+		g.schemas[g.names[t]] = nil
+		schema := g.structSchema(t)
+		g.schemas[g.names[t]] = schema
+	}
+
+	ref := openapi.RefTo(g.names[t])
+	g.refs[t] = append(g.refs[t], ref)
+	return ref
+}
+
+var invalidNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// nameTypes names the types that share an unqualified name. A type has the
+// plain name while it is the only one; once another type collides with it,
+// all of them are qualified with their package name, or with their import
+// path when package names collide too. Names then do not depend on the order
+// in which types are registered, except for types declared in functions of
+// the same package, which are numbered.
+func (g *Generator) nameTypes(base string) {
+	types := g.types[base]
+
+	names := make([]string, len(types))
+	for i := range types {
+		names[i] = base
+	}
+	if len(types) > 1 {
+		for i, t := range types {
+			names[i] = sanitizeName(path.Base(t.PkgPath()) + "." + typeName(t))
+		}
+		if hasDuplicates(names) {
+			for i, t := range types {
+				names[i] = sanitizeName(t.PkgPath() + "." + typeName(t))
+			}
+		}
+		count := map[string]int{}
+		for i, name := range names {
+			count[name]++
+			if count[name] > 1 {
+				names[i] = name + "_" + strconv.Itoa(count[name])
+			}
+		}
+	}
+
+	// Take the schemas out first, so that renaming one type cannot overwrite
+	// the schema of another.
+	schemas := map[reflect.Type]*openapi.Schema{}
+	for _, t := range types {
+		if name, ok := g.names[t]; ok {
+			schemas[t] = g.schemas[name]
+			delete(g.schemas, name)
+		}
+	}
+
+	renamed := false
+	for i, t := range types {
+		if schema, ok := schemas[t]; ok {
+			g.schemas[names[i]] = schema
+			renamed = renamed || g.names[t] != names[i]
+		}
+		g.names[t] = names[i]
+		for _, ref := range g.refs[t] {
+			ref.Ref = openapi.RefTo(names[i]).Ref
+		}
+	}
+
+	if renamed {
+		for _, d := range g.discriminators {
+			d.update()
+		}
+	}
+}
+
+func hasDuplicates(names []string) bool {
+	seen := map[string]bool{}
+	for _, name := range names {
+		if seen[name] {
+			return true
+		}
+		seen[name] = true
+	}
+	return false
+}
+
+var typeArgPackage = regexp.MustCompile(`[A-Za-z0-9_./-]+\.`)
+
+// typeName returns the name of t with packages dropped from the type
+// arguments of generic types, e.g. Page[Book] for Page[example.com/m.Book].
+func typeName(t reflect.Type) string {
+	return typeArgPackage.ReplaceAllString(t.Name(), "")
+}
+
+func sanitizeName(name string) string {
+	return strings.Trim(invalidNameChars.ReplaceAllString(name, "_"), "_")
+}
@@ -0,0 +1,108 @@
+package swagger_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/sattvikc/go-simpleapi/openapi"
+	"github.com/sattvikc/go-simpleapi/swagger"
+	"github.com/stretchr/testify/assert"
+)
+
+type Book struct {
+	Title string `json:"title"`
+}
+
+type CreateBookOK struct {
+	Book Book `json:"book"`
+}
+
+type CreateBookExists struct {
+	Book *Book `json:"book"`
+}
+
+type Category struct {
+	Name     string      `json:"name"`
+	Children []*Category `json:"children"`
+}
+
+type Buffer struct {
+	Size int `json:"size"`
+}
+
+func TestNamedStructsAreReferenced(t *testing.T) {
+	g := swagger.NewGenerator()
+
+	assert.Equal(t, openapi.RefTo("CreateBookOK"), g.GetSwaggerSchemaForType(reflect.TypeOf(CreateBookOK{})))
+	assert.Equal(t, openapi.RefTo("CreateBookExists"), g.GetSwaggerSchemaForType(reflect.TypeOf(&CreateBookExists{})))
+
+	assert.Len(t, g.Schemas(), 3)
+	assert.Equal(t, openapi.RefTo("Book"), g.Schemas()["CreateBookOK"].Properties["book"])
+	assert.Equal(t, &openapi.Schema{
+		AllOf:    []*openapi.Schema{openapi.RefTo("Book")},
+		Nullable: true,
+	}, g.Schemas()["CreateBookExists"].Properties["book"])
+}
+
+func TestAnonymousStructsAreInlined(t *testing.T) {
+	g := swagger.NewGenerator()
+
+	schema := g.GetSwaggerSchemaForType(reflect.TypeOf(struct {
+		Status string `json:"status"`
+	}{}))
+	assert.Equal(t, openapi.Types{"object"}, schema.Type)
+	assert.Empty(t, g.Schemas())
+}
+
+func TestRecursiveTypes(t *testing.T) {
+	g := swagger.NewGenerator()
+
+	g.GetSwaggerSchemaForType(reflect.TypeOf(Category{}))
+	assert.Equal(t, &openapi.Schema{
+		Type: openapi.Types{"array"},
+		Items: &openapi.Schema{
+			AllOf:    []*openapi.Schema{openapi.RefTo("Category")},
+			Nullable: true,
+		},
+		Nullable: true,
+	}, g.Schemas()["Category"].Properties["children"])
+}
+
+func TestNameCollisionsAcrossPackages(t *testing.T) {
+	type holder struct {
+		Buffer *Buffer `json:"buffer"`
+	}
+
+	for _, types := range [][]reflect.Type{
+		{reflect.TypeOf(holder{}), reflect.TypeOf(bytes.Buffer{})},
+		{reflect.TypeOf(bytes.Buffer{}), reflect.TypeOf(holder{})},
+	} {
+		g := swagger.NewGenerator()
+
+		g.GetSwaggerSchemaForType(types[0])
+		g.GetSwaggerSchemaForType(types[1])
+
+		name, _ := g.SchemaName(reflect.TypeOf(Buffer{}))
+		assert.Equal(t, "swagger_test.Buffer", name)
+		name, _ = g.SchemaName(reflect.TypeOf(bytes.Buffer{}))
+		assert.Equal(t, "bytes.Buffer", name)
+		assert.Contains(t, g.Schemas(), "swagger_test.Buffer")
+		assert.Contains(t, g.Schemas(), "bytes.Buffer")
+		assert.NotContains(t, g.Schemas(), "Buffer")
+
+		// References made before the collision are renamed too.
+		assert.Equal(t, openapi.RefTo("swagger_test.Buffer"), g.Schemas()["holder"].Properties["buffer"].AllOf[0])
+	}
+}
+
+func TestDiscriminatorFollowsRenames(t *testing.T) {
+	g := swagger.NewGenerator()
+
+	variant := g.GetSwaggerSchemaForType(reflect.TypeOf(Buffer{}))
+	d := g.Discriminator("kind", map[string]*openapi.Schema{"buffer": variant})
+	assert.Equal(t, map[string]string{"buffer": "#/components/schemas/Buffer"}, d.Mapping)
+
+	g.GetSwaggerSchemaForType(reflect.TypeOf(bytes.Buffer{}))
+	assert.Equal(t, map[string]string{"buffer": "#/components/schemas/swagger_test.Buffer"}, d.Mapping)
+}
@@ -1,178 +1,160 @@
 package swagger
 
 import (
-	"mime/multipart"
+	"fmt"
 	"reflect"
 	"strings"
+
+	"github.com/sattvikc/go-simpleapi/openapi"
 )
 
-func UpdateDefinitionUsingParamTypes(definition map[string]interface{}, paramTypes []reflect.Type) {
-	HEADER_EXCLUSIONS := map[string]bool{"content-type": true, "content-length": true, "user-agent": true}
+func (g *Generator) UpdateOperationUsingParamTypes(op *openapi.Operation, paramTypes []reflect.Type) {
+	// Accept, Content-Type and Authorization are ignored as parameters by the
+	// OpenAPI spec; Authorization is documented through security schemes.
+	HEADER_EXCLUSIONS := map[string]bool{"accept": true, "authorization": true, "content-type": true, "content-length": true, "user-agent": true}
 
 	for _, paramType := range paramTypes {
+		if paramType.Kind() != reflect.Struct {
+			continue
+		}
+
 		for i := 0; i < paramType.NumField(); i++ {
 			field := paramType.Field(i)
 			if field.Tag.Get("body") != "" {
 				if field.Tag.Get("body") == "multipart" {
-					properties := map[string]interface{}{}
-
-					for j := 0; j < field.Type.NumField(); j++ {
-						field := field.Type.Field(j)
-						if field.Tag.Get("form") != "" {
-							properties[field.Tag.Get("form")] = GetSwaggerSchemaForType(field.Type)
-						}
-					}
-
-					bodyDefinition := map[string]interface{}{
-						"content": map[string]interface{}{
-							"multipart/form-data": map[string]interface{}{
-								"schema": map[string]interface{}{
-									"type":       "object",
-									"properties": properties,
-								},
+					op.RequestBody = &openapi.RequestBody{
+						Content: map[string]*openapi.MediaType{
+							"multipart/form-data": {
+								Schema: g.formSchema(field.Type),
 							},
 						},
 					}
 
-					definition["requestBody"] = bodyDefinition
-
 				} else if field.Tag.Get("body") == "urlencoded" {
-					properties := map[string]interface{}{}
-
-					for j := 0; j < field.Type.NumField(); j++ {
-						field := field.Type.Field(j)
-						if field.Tag.Get("form") != "" {
-							properties[field.Tag.Get("form")] = GetSwaggerSchemaForType(field.Type)
-						}
-					}
-
-					bodyDefinition := map[string]interface{}{
-						"content": map[string]interface{}{
-							"application/x-www-form-urlencoded": map[string]interface{}{
-								"schema": map[string]interface{}{
-									"type":       "object",
-									"properties": properties,
-								},
+					op.RequestBody = &openapi.RequestBody{
+						Content: map[string]*openapi.MediaType{
+							"application/x-www-form-urlencoded": {
+								Schema: g.formSchema(field.Type),
 							},
 						},
 					}
 
-					definition["requestBody"] = bodyDefinition
 				} else if field.Tag.Get("body") == "json" {
-					bodyDefinition := map[string]interface{}{
-						"content": map[string]interface{}{
-							"application/json": map[string]interface{}{
-								"schema": GetSwaggerSchemaForType(field.Type),
+					op.RequestBody = &openapi.RequestBody{
+						Content: map[string]*openapi.MediaType{
+							"application/json": {
+								Schema: g.GetSwaggerSchemaForType(field.Type),
 							},
 						},
 					}
-					definition["requestBody"] = bodyDefinition
 				}
 
 			} else if field.Type.Kind() == reflect.Struct {
-				UpdateDefinitionUsingParamTypes(definition, []reflect.Type{field.Type})
+				g.UpdateOperationUsingParamTypes(op, []reflect.Type{field.Type})
 
 			} else if field.Tag.Get("query") != "" {
-				queryDefinition := map[string]interface{}{
-					"in":       "query",
-					"name":     field.Tag.Get("query"),
-					"required": field.Type.Kind() != reflect.Ptr,
-					"schema":   GetSwaggerSchemaForType(field.Type),
+				parameter := &openapi.Parameter{
+					In:       "query",
+					Name:     field.Tag.Get("query"),
+					Required: field.Type.Kind() != reflect.Ptr,
+					Schema:   g.GetSwaggerSchemaForType(field.Type),
 				}
-				definition["parameters"] = append(definition["parameters"].([]interface{}), queryDefinition)
+				annotateParameter(parameter, field.Tag)
+				op.Parameters = append(op.Parameters, parameter)
 
 			} else if field.Tag.Get("header") != "" {
 				if _, ok := HEADER_EXCLUSIONS[strings.ToLower(field.Tag.Get("header"))]; ok {
 					continue
 				}
-				headerDefinition := map[string]interface{}{
-					"in":       "header",
-					"name":     field.Tag.Get("header"),
-					"required": field.Type.Kind() != reflect.Ptr,
-					"schema":   GetSwaggerSchemaForType(field.Type),
+				parameter := &openapi.Parameter{
+					In:       "header",
+					Name:     field.Tag.Get("header"),
+					Required: field.Type.Kind() != reflect.Ptr,
+					Schema:   g.GetSwaggerSchemaForType(field.Type),
 				}
-				definition["parameters"] = append(definition["parameters"].([]interface{}), headerDefinition)
+				annotateParameter(parameter, field.Tag)
+				op.Parameters = append(op.Parameters, parameter)
 
 			} else if field.Tag.Get("path") != "" {
-				pathDefinition := map[string]interface{}{
-					"in":       "path",
-					"name":     field.Tag.Get("path"),
-					"required": true, // TODO
-					"schema": map[string]interface{}{
-						"type": "string",
-					},
+				parameter := &openapi.Parameter{
+					In:       "path",
+					Name:     field.Tag.Get("path"),
+					Required: true,
+					Schema:   g.GetSwaggerSchemaForType(field.Type),
 				}
-				definition["parameters"] = append(definition["parameters"].([]interface{}), pathDefinition)
+				annotateParameter(parameter, field.Tag)
+				op.Parameters = append(op.Parameters, parameter)
 			}
 		}
 	}
 }
 
-func GetSwaggerSchemaForType(t reflect.Type) interface{} {
-	fileType := reflect.TypeOf((*multipart.File)(nil)).Elem()
-	if t.ConvertibleTo(fileType) {
-		return map[string]interface{}{
-			"type": "file",
+func (g *Generator) formSchema(t reflect.Type) *openapi.Schema {
+	properties := map[string]*openapi.Schema{}
+
+	for j := 0; j < t.NumField(); j++ {
+		field := t.Field(j)
+		if field.Tag.Get("form") != "" {
+			properties[field.Tag.Get("form")] = annotateSchema(g.GetSwaggerSchemaForType(field.Type), field.Tag)
 		}
 	}
 
-	if t.Kind() == reflect.Ptr {
-		t = t.Elem()
+	return &openapi.Schema{
+		Type:       openapi.Types{"object"},
+		Properties: properties,
 	}
+}
 
-	if t.Kind() == reflect.Struct {
-		properties := map[string]interface{}{}
-
-		required := []string{}
-		for i := 0; i < t.NumField(); i++ {
-			field := t.Field(i)
-			if field.Tag.Get("json") != "" {
-				properties[field.Tag.Get("json")] = GetSwaggerSchemaForType(field.Type)
-
-				if field.Type.Kind() != reflect.Ptr {
-					required = append(required, field.Tag.Get("json"))
-				}
-			}
+// ResponseHeader documents a header:"..." field of a response struct, as
+// reflection.WriteResponseHeaders sends it.
+func (g *Generator) ResponseHeader(field reflect.StructField) *openapi.Header {
+	var schema *openapi.Schema
+	if t := field.Type; t == timeType || t.Kind() == reflect.Ptr && t.Elem() == timeType {
+		// Times are sent as an HTTP-date, not as RFC 3339.
+		schema = &openapi.Schema{
+			Type:        openapi.Types{"string"},
+			Description: "HTTP-date, e.g. Mon, 02 Jan 2006 15:04:05 GMT",
 		}
+	} else {
+		schema = g.GetSwaggerSchemaForType(field.Type)
+	}
+	header := &openapi.Header{
+		Required: alwaysSent(field.Type),
+		Schema:   schema,
+	}
 
-		return map[string]interface{}{
-			"type":       "object",
-			"properties": properties,
-			"required":   required,
-		}
+	header.Description = field.Tag.Get("doc")
+	if example, ok := field.Tag.Lookup("example"); ok {
+		header.Example = parseTagValue(schema, example)
+	}
+	header.Deprecated = tagFlag(field.Tag, "deprecated")
+	if format := field.Tag.Get("format"); format != "" {
+		schema.Format = format
 	}
+	if enum := field.Tag.Get("enum"); enum != "" {
+		schema.Enum = parseEnum(schema, enum)
+	}
+	return header
+}
 
-	if t.Kind() == reflect.Slice {
-		return map[string]interface{}{
-			"type":  "array",
-			"items": GetSwaggerSchemaForType(t.Elem()),
-		}
+var stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+
+// alwaysSent reports whether a header field of type t is sent whatever its
+// value. Nil pointers, empty slices and values formatted as an empty string
+// are left out.
+func alwaysSent(t reflect.Type) bool {
+	if t == timeType {
+		return true
+	}
+	if t.Implements(textMarshalerType) || t.Implements(stringerType) {
+		return false
 	}
 
 	switch t.Kind() {
-	case reflect.String:
-		return map[string]interface{}{
-			"type": "string",
-		}
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		return map[string]interface{}{
-			"type": "integer",
-		}
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		return map[string]interface{}{
-			"type": "integer",
-		}
-	case reflect.Float32, reflect.Float64:
-		return map[string]interface{}{
-			"type": "number",
-		}
-	case reflect.Bool:
-		return map[string]interface{}{
-			"type": "boolean",
-		}
-	default:
-		return map[string]interface{}{
-			"type": "string",
-		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.Bool:
+		return true
 	}
+	return false
 }
@@ -0,0 +1,325 @@
+package swagger
+
+import (
+	"encoding"
+	"encoding/json"
+	"mime/multipart"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sattvikc/go-simpleapi/openapi"
+)
+
+var (
+	fileType          = reflect.TypeOf((*multipart.File)(nil)).Elem()
+	timeType          = reflect.TypeOf(time.Time{})
+	rawMessageType    = reflect.TypeOf(json.RawMessage{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// GetSwaggerSchemaForType returns the schema of the JSON encoding of t, as
+// produced by encoding/json.
+func (g *Generator) GetSwaggerSchemaForType(t reflect.Type) *openapi.Schema {
+	if t.Kind() == reflect.Ptr && !t.ConvertibleTo(fileType) {
+		t = t.Elem()
+	}
+	return g.schema(t)
+}
+
+func (g *Generator) schema(t reflect.Type) *openapi.Schema {
+	if t.ConvertibleTo(fileType) {
+		return &openapi.Schema{Type: openapi.Types{"string"}, Format: "binary"}
+	}
+
+	if t.Kind() == reflect.Ptr {
+		return nullable(g.schema(t.Elem()))
+	}
+
+	switch {
+	case t == timeType:
+		return &openapi.Schema{Type: openapi.Types{"string"}, Format: "date-time"}
+	case t == rawMessageType:
+		return &openapi.Schema{}
+	case implements(t, jsonMarshalerType):
+		// The encoding is up to the type, nothing can be said about it.
+		return &openapi.Schema{}
+	case implements(t, textMarshalerType):
+		return &openapi.Schema{Type: openapi.Types{"string"}}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if t.Name() != "" {
+			return g.ref(t)
+		}
+		return g.structSchema(t)
+
+	// Nil maps and slices are encoded as null.
+	case reflect.Map:
+		return nullable(&openapi.Schema{
+			Type:                 openapi.Types{"object"},
+			AdditionalProperties: g.schema(t.Elem()),
+		})
+
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 && !implements(t.Elem(), jsonMarshalerType) && !implements(t.Elem(), textMarshalerType) {
+			return nullable(&openapi.Schema{Type: openapi.Types{"string"}, Format: "byte"})
+		}
+		return nullable(&openapi.Schema{
+			Type:  openapi.Types{"array"},
+			Items: g.schema(t.Elem()),
+		})
+
+	case reflect.Array:
+		return &openapi.Schema{
+			Type:     openapi.Types{"array"},
+			Items:    g.schema(t.Elem()),
+			MinItems: openapi.Int(t.Len()),
+			MaxItems: openapi.Int(t.Len()),
+		}
+
+	case reflect.Interface:
+		return &openapi.Schema{}
+
+	case reflect.String:
+		return &openapi.Schema{Type: openapi.Types{"string"}}
+
+	case reflect.Int, reflect.Int64:
+		return &openapi.Schema{Type: openapi.Types{"integer"}, Format: "int64"}
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return &openapi.Schema{Type: openapi.Types{"integer"}, Format: "int32"}
+	case reflect.Uint, reflect.Uint64, reflect.Uintptr:
+		return &openapi.Schema{Type: openapi.Types{"integer"}, Format: "int64", Minimum: openapi.Float(0)}
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &openapi.Schema{Type: openapi.Types{"integer"}, Format: "int32", Minimum: openapi.Float(0)}
+
+	case reflect.Float32:
+		return &openapi.Schema{Type: openapi.Types{"number"}, Format: "float"}
+	case reflect.Float64:
+		return &openapi.Schema{Type: openapi.Types{"number"}, Format: "double"}
+
+	case reflect.Bool:
+		return &openapi.Schema{Type: openapi.Types{"boolean"}}
+
+	default:
+		return &openapi.Schema{Type: openapi.Types{"string"}}
+	}
+}
+
+func (g *Generator) structSchema(t reflect.Type) *openapi.Schema {
+	schema := &openapi.Schema{
+		Type:       openapi.Types{"object"},
+		Properties: map[string]*openapi.Schema{},
+	}
+
+	for _, field := range jsonFields(t) {
+		property := g.schema(field.typ)
+		if field.asString {
+			property = &openapi.Schema{Type: openapi.Types{"string"}}
+		}
+		schema.Properties[field.name] = annotateSchema(property, field.tag)
+
+		if !field.omitEmpty && field.typ.Kind() != reflect.Ptr {
+			schema.Required = append(schema.Required, field.name)
+		}
+	}
+
+	return schema
+}
+
+// nullable marks a schema as accepting null. A $ref cannot have siblings in
+// OpenAPI 3.0, so references are wrapped in allOf.
+func nullable(schema *openapi.Schema) *openapi.Schema {
+	if schema.Ref != "" {
+		return &openapi.Schema{
+			AllOf:    []*openapi.Schema{schema},
+			Nullable: true,
+		}
+	}
+	if schema.Type == nil {
+		return schema
+	}
+	schema.Nullable = true
+	return schema
+}
+
+func implements(t, iface reflect.Type) bool {
+	return t.Implements(iface) || reflect.PtrTo(t).Implements(iface)
+}
+
+type jsonField struct {
+	name      string
+	typ       reflect.Type
+	tag       reflect.StructTag
+	index     []int
+	tagged    bool
+	omitEmpty bool
+	asString  bool
+}
+
+// jsonFields returns the fields encoding/json serializes for the struct type
+// t, following its rules for tags, embedded structs and name conflicts.
+func jsonFields(t reflect.Type) []jsonField {
+	type queued struct {
+		typ   reflect.Type
+		index []int
+	}
+
+	current := []queued{}
+	next := []queued{{typ: t}}
+	visited := map[reflect.Type]bool{}
+
+	var fields []jsonField
+
+	for len(next) > 0 {
+		current, next = next, current[:0]
+
+		for _, q := range current {
+			if visited[q.typ] {
+				continue
+			}
+			visited[q.typ] = true
+
+			for i := 0; i < q.typ.NumField(); i++ {
+				sf := q.typ.Field(i)
+
+				if sf.Anonymous {
+					ft := sf.Type
+					if ft.Kind() == reflect.Ptr {
+						ft = ft.Elem()
+					}
+					if !sf.IsExported() && ft.Kind() != reflect.Struct {
+						continue
+					}
+				} else if !sf.IsExported() {
+					continue
+				}
+
+				tag := sf.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, opts, _ := strings.Cut(tag, ",")
+				if !isValidTag(name) {
+					name = ""
+				}
+
+				index := make([]int, len(q.index)+1)
+				copy(index, q.index)
+				index[len(q.index)] = i
+
+				ft := sf.Type
+				if ft.Name() == "" && ft.Kind() == reflect.Ptr {
+					ft = ft.Elem()
+				}
+
+				if name != "" || !sf.Anonymous || ft.Kind() != reflect.Struct {
+					field := jsonField{
+						name:      name,
+						typ:       sf.Type,
+						tag:       sf.Tag,
+						index:     index,
+						tagged:    name != "",
+						omitEmpty: hasOption(opts, "omitempty"),
+					}
+					if field.name == "" {
+						field.name = sf.Name
+					}
+					if hasOption(opts, "string") {
+						switch ft.Kind() {
+						case reflect.Bool,
+							reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+							reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+							reflect.Float32, reflect.Float64,
+							reflect.String:
+							field.asString = true
+						}
+					}
+
+					fields = append(fields, field)
+					continue
+				}
+
+				next = append(next, queued{typ: ft, index: index})
+			}
+		}
+	}
+
+	sort.SliceStable(fields, func(i, j int) bool {
+		if fields[i].name != fields[j].name {
+			return fields[i].name < fields[j].name
+		}
+		if len(fields[i].index) != len(fields[j].index) {
+			return len(fields[i].index) < len(fields[j].index)
+		}
+		return fields[i].tagged && !fields[j].tagged
+	})
+
+	dominant := fields[:0]
+	for i := 0; i < len(fields); {
+		j := i + 1
+		for j < len(fields) && fields[j].name == fields[i].name {
+			j++
+		}
+		if field, ok := dominantField(fields[i:j]); ok {
+			dominant = append(dominant, field)
+		}
+		i = j
+	}
+
+	sort.Slice(dominant, func(i, j int) bool {
+		return indexLess(dominant[i].index, dominant[j].index)
+	})
+
+	return dominant
+}
+
+// dominantField picks the field that wins among fields sharing a name: the
+// shallowest, and among those the only tagged one. Any other conflict hides
+// the name entirely, as in encoding/json.
+func dominantField(fields []jsonField) (jsonField, bool) {
+	if len(fields) > 1 && len(fields[0].index) == len(fields[1].index) && fields[0].tagged == fields[1].tagged {
+		return jsonField{}, false
+	}
+	return fields[0], true
+}
+
+func indexLess(a, b []int) bool {
+	for k, x := range a {
+		if k >= len(b) {
+			return false
+		}
+		if x != b[k] {
+			return x < b[k]
+		}
+	}
+	return len(a) < len(b)
+}
+
+func hasOption(opts, option string) bool {
+	for opts != "" {
+		var name string
+		name, opts, _ = strings.Cut(opts, ",")
+		if name == option {
+			return true
+		}
+	}
+	return false
+}
+
+func isValidTag(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		switch {
+		case strings.ContainsRune("!#$%&()*+-./:;<=>?@[]^_{|}~ ", c):
+		case !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c > 127):
+			return false
+		}
+	}
+	return true
+}
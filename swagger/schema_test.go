@@ -0,0 +1,163 @@
+package swagger_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sattvikc/go-simpleapi/openapi"
+	"github.com/sattvikc/go-simpleapi/swagger"
+	"github.com/stretchr/testify/assert"
+)
+
+type Base struct {
+	ID      int64  `json:"id"`
+	Created string `json:"created"`
+}
+
+type Audit struct {
+	Created string `json:"created"`
+	Updated string
+}
+
+type Article struct {
+	Base
+	*Audit
+	Title    string             `json:"title"`
+	Summary  string             `json:"summary,omitempty"`
+	Hidden   string             `json:"-"`
+	Dash     string             `json:"-,"`
+	Count    int                `json:",string"`
+	Score    float32            `json:"score"`
+	Ratio    float64            `json:"ratio"`
+	Small    int32              `json:"small"`
+	Unsigned uint16             `json:"unsigned"`
+	Raw      []byte             `json:"raw"`
+	Hash     [4]byte            `json:"hash"`
+	Labels   map[string]string  `json:"labels"`
+	Extra    interface{}        `json:"extra"`
+	Message  json.RawMessage    `json:"message"`
+	Parent   *string            `json:"parent"`
+	At       time.Time          `json:"at"`
+	Counts   map[string][]int32 `json:"counts"`
+	internal string
+}
+
+func TestSchemaFollowsEncodingJSON(t *testing.T) {
+	g := swagger.NewGenerator()
+	g.GetSwaggerSchemaForType(reflect.TypeOf(Article{}))
+
+	schema := g.Schemas()["Article"]
+	properties := schema.Properties
+
+	assert.ElementsMatch(t, []string{
+		"id", "Updated", "title", "summary", "-", "Count", "score", "ratio", "small", "unsigned",
+		"raw", "hash", "labels", "extra", "message", "parent", "at", "counts",
+	}, keys(properties))
+
+	// Both embedded structs tag a "created" field at the same depth, so
+	// encoding/json drops it.
+	assert.NotContains(t, properties, "created")
+
+	assert.Equal(t, &openapi.Schema{Type: openapi.Types{"integer"}, Format: "int64"}, properties["id"])
+	assert.Equal(t, &openapi.Schema{Type: openapi.Types{"string"}}, properties["Count"])
+	assert.Equal(t, &openapi.Schema{Type: openapi.Types{"number"}, Format: "float"}, properties["score"])
+	assert.Equal(t, &openapi.Schema{Type: openapi.Types{"number"}, Format: "double"}, properties["ratio"])
+	assert.Equal(t, &openapi.Schema{Type: openapi.Types{"integer"}, Format: "int32"}, properties["small"])
+	assert.Equal(t, &openapi.Schema{Type: openapi.Types{"integer"}, Format: "int32", Minimum: openapi.Float(0)}, properties["unsigned"])
+	// Nil slices and maps are encoded as null.
+	assert.Equal(t, &openapi.Schema{Type: openapi.Types{"string"}, Format: "byte", Nullable: true}, properties["raw"])
+	assert.Equal(t, &openapi.Schema{
+		Type:     openapi.Types{"array"},
+		Items:    &openapi.Schema{Type: openapi.Types{"integer"}, Format: "int32", Minimum: openapi.Float(0)},
+		MinItems: openapi.Int(4),
+		MaxItems: openapi.Int(4),
+	}, properties["hash"])
+	assert.Equal(t, &openapi.Schema{
+		Type:                 openapi.Types{"object"},
+		AdditionalProperties: &openapi.Schema{Type: openapi.Types{"string"}},
+		Nullable:             true,
+	}, properties["labels"])
+	assert.Equal(t, &openapi.Schema{}, properties["extra"])
+	assert.Equal(t, &openapi.Schema{}, properties["message"])
+	assert.Equal(t, &openapi.Schema{Type: openapi.Types{"string"}, Nullable: true}, properties["parent"])
+	assert.Equal(t, &openapi.Schema{Type: openapi.Types{"string"}, Format: "date-time"}, properties["at"])
+
+	assert.NotContains(t, schema.Required, "summary")
+	assert.NotContains(t, schema.Required, "parent")
+	assert.Contains(t, schema.Required, "title")
+}
+
+func keys(m map[string]*openapi.Schema) []string {
+	result := []string{}
+	for k := range m {
+		result = append(result, k)
+	}
+	return result
+}
+
+type Annotated struct {
+	Email  string   `json:"email" doc:"Contact address" format:"email" example:"a@b.c"`
+	Status string   `json:"status" enum:"ok, exists"`
+	Codes  []int    `json:"codes" enum:"1,2"`
+	Old    bool     `json:"old" deprecated:"true"`
+	ID     string   `json:"id" readOnly:"true"`
+	Secret string   `json:"secret" writeOnly:"true"`
+	Book   Book     `json:"book" doc:"The book"`
+	Size   *float64 `json:"size" example:"1.5"`
+}
+
+func TestSchemaAnnotations(t *testing.T) {
+	g := swagger.NewGenerator()
+	g.GetSwaggerSchemaForType(reflect.TypeOf(Annotated{}))
+
+	properties := g.Schemas()["Annotated"].Properties
+
+	assert.Equal(t, &openapi.Schema{Type: openapi.Types{"string"}, Format: "email", Description: "Contact address", Example: "a@b.c"}, properties["email"])
+	assert.Equal(t, &openapi.Schema{Type: openapi.Types{"string"}, Enum: []interface{}{"ok", "exists"}}, properties["status"])
+	assert.Equal(t, []interface{}{int64(1), int64(2)}, properties["codes"].Enum)
+	assert.True(t, properties["old"].Deprecated)
+	assert.True(t, properties["id"].ReadOnly)
+	assert.True(t, properties["secret"].WriteOnly)
+	assert.Equal(t, &openapi.Schema{
+		AllOf:       []*openapi.Schema{openapi.RefTo("Book")},
+		Description: "The book",
+	}, properties["book"])
+	assert.Equal(t, 1.5, properties["size"].Example)
+}
+
+func TestParameterAnnotations(t *testing.T) {
+	g := swagger.NewGenerator()
+	op := &openapi.Operation{Parameters: []*openapi.Parameter{}}
+
+	g.UpdateOperationUsingParamTypes(op, []reflect.Type{reflect.TypeOf(struct {
+		ID    int    `path:"id" doc:"Book id" example:"42"`
+		Sort  string `query:"sort" enum:"asc,desc"`
+		Trace string `header:"X-Trace" deprecated:"true"`
+	}{})})
+
+	assert.Equal(t, []*openapi.Parameter{
+		{
+			In:          "path",
+			Name:        "id",
+			Required:    true,
+			Description: "Book id",
+			Example:     int64(42),
+			Schema:      &openapi.Schema{Type: openapi.Types{"integer"}, Format: "int64"},
+		},
+		{
+			In:       "query",
+			Name:     "sort",
+			Required: true,
+			Schema:   &openapi.Schema{Type: openapi.Types{"string"}, Enum: []interface{}{"asc", "desc"}},
+		},
+		{
+			In:         "header",
+			Name:       "X-Trace",
+			Required:   true,
+			Deprecated: true,
+			Schema:     &openapi.Schema{Type: openapi.Types{"string"}},
+		},
+	}, op.Parameters)
+}
@@ -0,0 +1,45 @@
+//go:build ignore
+
+// fetch downloads the pinned documentation assets into dist. Run it with
+// go generate from this directory and commit the result.
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/sattvikc/go-simpleapi/ui"
+)
+
+func main() {
+	for _, asset := range ui.All {
+		if err := fetch(asset); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+}
+
+func fetch(asset ui.Asset) error {
+	resp, err := http.Get(asset.CDN)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", asset.CDN, resp.Status)
+	}
+
+	f, err := os.Create(filepath.Join("dist", asset.Name))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(f, resp.Body)
+	return err
+}
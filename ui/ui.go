@@ -0,0 +1,50 @@
+// Package ui embeds the static assets of the documentation pages so that
+// they work without access to a CDN.
+//
+// Swagger UI and its favicon are embedded. The ReDoc bundle and the Swagger
+// UI theme are not; run go generate in this directory to download the pinned
+// versions of all assets into dist, or provide them with
+// simpleapi.WithDocsAssets.
+package ui
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:generate go run fetch.go
+
+//go:embed dist/*.js dist/*.css dist/*.png
+var dist embed.FS
+
+// Assets holds the embedded files, e.g. swagger-ui-bundle.js.
+var Assets, _ = fs.Sub(dist, "dist")
+
+// Asset describes a file served to the documentation pages and where it is
+// published.
+type Asset struct {
+	Name string
+	CDN  string
+}
+
+var (
+	SwaggerUICSS    = Asset{Name: "swagger-ui.css", CDN: "https://cdn.jsdelivr.net/npm/swagger-ui-dist@" + SwaggerUIVersion + "/swagger-ui.css"}
+	SwaggerUIBundle = Asset{Name: "swagger-ui-bundle.js", CDN: "https://cdn.jsdelivr.net/npm/swagger-ui-dist@" + SwaggerUIVersion + "/swagger-ui-bundle.js"}
+	SwaggerUITheme  = Asset{Name: "theme-muted.min.css", CDN: "https://cdn.jsdelivr.net/npm/swagger-ui-themes@" + SwaggerUIThemesVersion + "/themes/3.x/theme-muted.min.css"}
+	Favicon         = Asset{Name: "favicon-32x32.png", CDN: "https://cdn.jsdelivr.net/npm/swagger-ui-dist@" + SwaggerUIVersion + "/favicon-32x32.png"}
+	ReDocBundle     = Asset{Name: "redoc.standalone.js", CDN: "https://cdn.jsdelivr.net/npm/redoc@" + ReDocVersion + "/bundles/redoc.standalone.js"}
+
+	All = []Asset{SwaggerUICSS, SwaggerUIBundle, SwaggerUITheme, Favicon, ReDocBundle}
+)
+
+const (
+	SwaggerUIVersion       = "5.18.2"
+	SwaggerUIThemesVersion = "3.0.1"
+	ReDocVersion           = "2.1.3"
+)
+
+// Has reports whether the named asset exists in fsys.
+func Has(fsys fs.FS, name string) bool {
+	_, err := fs.Stat(fsys, name)
+	return err == nil
+}
@@ -0,0 +1,125 @@
+package simpleapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/sattvikc/go-simpleapi/openapi"
+)
+
+// ResponseValidationError reports a response that does not match what its
+// endpoint declares.
+type ResponseValidationError struct {
+	Method   string
+	Path     string
+	Status   int
+	Problems []string
+}
+
+func (e *ResponseValidationError) Error() string {
+	return fmt.Sprintf("%s %s responded %d: %s", e.Method, e.Path, e.Status, strings.Join(e.Problems, "; "))
+}
+
+// WithResponseValidation checks every response of an endpoint against the
+// responses it declares: the status code must be declared and a JSON body
+// must match the declared schema. Mismatches are passed to report, or logged
+// when report is nil. In tests, report can fail the test:
+//
+//	simpleapi.WithResponseValidation(func(err *simpleapi.ResponseValidationError) {
+//		t.Error(err)
+//	})
+//
+// Validation buffers JSON bodies and is meant for development and tests.
+func WithResponseValidation(report func(err *ResponseValidationError)) Option {
+	if report == nil {
+		report = func(err *ResponseValidationError) {
+			log.Println("simpleapi:", err)
+		}
+	}
+	return func(s *App) {
+		s.validate = report
+	}
+}
+
+func (s *App) validateResponse(e *Endpoint, ctx *Context, body []byte) *ResponseValidationError {
+	spec, err := s.cachedSpec()
+	if err != nil {
+		return nil
+	}
+
+	item := spec.doc.Paths[e.path]
+	if item == nil || item.Operation(e.method) == nil {
+		return nil
+	}
+	op := item.Operation(e.method)
+	if len(op.Responses) == 0 {
+		return nil
+	}
+
+	status := ctx.Response.Status()
+	if status == 0 {
+		status = http.StatusOK
+	}
+	if status == http.StatusSwitchingProtocols {
+		return nil
+	}
+
+	problems := validateAgainst(spec.doc, op, status, ctx.Response.Header().Get("Content-Type"), body)
+	if len(problems) == 0 {
+		return nil
+	}
+	return &ResponseValidationError{
+		Method:   ctx.Request.Method,
+		Path:     ctx.Request.URL.Path,
+		Status:   status,
+		Problems: problems,
+	}
+}
+
+func validateAgainst(doc *openapi.Document, op *openapi.Operation, status int, contentType string, body []byte) []string {
+	code := strconv.Itoa(status)
+	response := op.Responses[code]
+	if response == nil {
+		response = op.Responses[code[:1]+"XX"]
+	}
+	if response == nil {
+		response = op.Responses["default"]
+	}
+	if response == nil {
+		return []string{fmt.Sprintf("status %d is not declared", status)}
+	}
+
+	if len(response.Content) == 0 {
+		return nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	media := response.Content[mediaType]
+	if media == nil {
+		return []string{fmt.Sprintf("content type %q is not declared", mediaType)}
+	}
+	if media.Schema == nil || !isJSON(mediaType) {
+		return nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return []string{"body is not valid JSON: " + err.Error()}
+	}
+
+	problems := []string{}
+	for _, verr := range doc.Validate(media.Schema, value) {
+		problems = append(problems, verr.Error())
+	}
+	return problems
+}
+
+func isJSON(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
@@ -0,0 +1,69 @@
+package simpleapi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sattvikc/go-simpleapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponseValidation(t *testing.T) {
+	var reported []*simpleapi.ResponseValidationError
+	app := simpleapi.New(simpleapi.WithResponseValidation(func(err *simpleapi.ResponseValidationError) {
+		reported = append(reported, err)
+	}))
+	app.Endpoint("/novels/{id}", func(e *simpleapi.Endpoint) interface{} {
+		e.WithResponse(200, Created{}, "Found").
+			WithResponse(404, nil, "Not found").
+			GET()
+		return func(ctx *simpleapi.Context, req struct {
+			ID string `path:"id"`
+		}) error {
+			switch req.ID {
+			case "1":
+				return ctx.JSON(200, Created{Status: "OK", ID: 1})
+			case "2":
+				return ctx.JSON(200, map[string]interface{}{"status": 2})
+			case "3":
+				return ctx.JSON(201, Created{})
+			default:
+				return simpleapi.NewHTTPError(404, nil)
+			}
+		}
+	})
+
+	for _, id := range []string{"1", "2", "3", "4"} {
+		app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/novels/"+id, nil))
+	}
+
+	assert.Len(t, reported, 2)
+	assert.Equal(t, "/novels/2", reported[0].Path)
+	assert.Equal(t, []string{`$: missing required property "id"`, "$.status: expected string, got integer"}, reported[0].Problems)
+	assert.Equal(t, 201, reported[1].Status)
+	assert.Equal(t, []string{"status 201 is not declared"}, reported[1].Problems)
+}
+
+func TestResponseValidationAcceptsNilSlicesAndMaps(t *testing.T) {
+	type list struct {
+		Items  []string          `json:"items"`
+		Labels map[string]string `json:"labels"`
+	}
+
+	var reported []*simpleapi.ResponseValidationError
+	app := simpleapi.New(simpleapi.WithResponseValidation(func(err *simpleapi.ResponseValidationError) {
+		reported = append(reported, err)
+	}))
+	app.Endpoint("/lists", func(e *simpleapi.Endpoint) interface{} {
+		e.WithResponse(200, list{}, "Lists").GET()
+		return func(ctx *simpleapi.Context) error {
+			return ctx.JSON(200, list{})
+		}
+	})
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/lists", nil))
+	assert.JSONEq(t, `{"items": null, "labels": null}`, w.Body.String())
+	assert.Empty(t, reported)
+}
@@ -0,0 +1,512 @@
+package websocket
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// Message types as defined by RFC 6455.
+const (
+	TextMessage   = 1
+	BinaryMessage = 2
+	CloseMessage  = 8
+	PingMessage   = 9
+	PongMessage   = 10
+)
+
+// Close codes as defined by RFC 6455.
+const (
+	CloseNormalClosure   = 1000
+	CloseGoingAway       = 1001
+	CloseProtocolError   = 1002
+	CloseMessageTooBig   = 1009
+	CloseNoStatusPresent = 1005
+)
+
+const (
+	continuationFrame = 0
+	finalBit          = 0x80
+	maskBit           = 0x80
+	magicKey          = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+)
+
+// DefaultReadLimit is the maximum message size accepted unless changed with
+// SetReadLimit.
+const DefaultReadLimit = 1 << 20
+
+var ErrReadLimit = errors.New("websocket: message exceeds read limit")
+
+// CloseError is returned by reads once the peer has closed the connection.
+type CloseError struct {
+	Code int
+	Text string
+}
+
+func (e *CloseError) Error() string {
+	return fmt.Sprintf("websocket: close %d %s", e.Code, e.Text)
+}
+
+// Conn is a WebSocket connection on either the server or the client side.
+type Conn struct {
+	conn      net.Conn
+	br        *bufio.Reader
+	isServer  bool
+	readLimit int64
+
+	writeMu sync.Mutex
+	closeMu sync.Mutex
+	closed  bool
+}
+
+// Upgrader performs the server side of the opening handshake.
+type Upgrader struct {
+	// CheckOrigin reports whether a request from its Origin is accepted.
+	// When nil, SameOrigin is used, so that other websites cannot open
+	// connections with the cookies of a user.
+	CheckOrigin func(r *http.Request) bool
+}
+
+// Upgrade performs the server side of the opening handshake with the default
+// Upgrader, which only accepts requests from the same origin.
+func Upgrade(w http.ResponseWriter, r *http.Request) (*Conn, error) {
+	return (&Upgrader{}).Upgrade(w, r)
+}
+
+// SameOrigin accepts requests without an Origin header, which are not sent
+// by browsers, and requests whose Origin has the host of the request.
+func SameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Host, r.Host)
+}
+
+// AllowOrigins returns a CheckOrigin function that accepts requests from the
+// same origin and from the given origins, like "https://example.com". The
+// origin "*" accepts every request.
+func AllowOrigins(origins ...string) func(r *http.Request) bool {
+	return func(r *http.Request) bool {
+		if SameOrigin(r) {
+			return true
+		}
+		origin := r.Header.Get("Origin")
+		for _, allowed := range origins {
+			if allowed == "*" || strings.EqualFold(allowed, origin) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Upgrade performs the server side of the opening handshake and takes over
+// the underlying connection.
+func (u *Upgrader) Upgrade(w http.ResponseWriter, r *http.Request) (*Conn, error) {
+	if r.Method != http.MethodGet {
+		return nil, handshakeError(w, http.StatusMethodNotAllowed, "request method is not GET")
+	}
+	if !headerContains(r.Header, "Connection", "upgrade") {
+		return nil, handshakeError(w, http.StatusBadRequest, "'upgrade' token not found in 'Connection' header")
+	}
+	if !headerContains(r.Header, "Upgrade", "websocket") {
+		return nil, handshakeError(w, http.StatusBadRequest, "'websocket' token not found in 'Upgrade' header")
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		return nil, handshakeError(w, http.StatusUpgradeRequired, "unsupported version")
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		return nil, handshakeError(w, http.StatusBadRequest, "'Sec-WebSocket-Key' header is missing")
+	}
+	checkOrigin := u.CheckOrigin
+	if checkOrigin == nil {
+		checkOrigin = SameOrigin
+	}
+	if !checkOrigin(r) {
+		return nil, handshakeError(w, http.StatusForbidden, "origin "+r.Header.Get("Origin")+" is not allowed")
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		return nil, handshakeError(w, http.StatusInternalServerError, "response writer does not implement http.Hijacker")
+	}
+	netConn, brw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, err
+	}
+	if brw.Reader.Buffered() > 0 {
+		netConn.Close()
+		return nil, errors.New("websocket: client sent data before handshake is complete")
+	}
+
+	response := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + acceptKey(key) + "\r\n\r\n"
+	if _, err := netConn.Write([]byte(response)); err != nil {
+		netConn.Close()
+		return nil, err
+	}
+
+	return newConn(netConn, brw.Reader, true), nil
+}
+
+// Dial opens a client connection to a ws:// or wss:// URL.
+func Dial(rawURL string, header http.Header) (*Conn, *http.Response, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var netConn net.Conn
+	switch u.Scheme {
+	case "ws":
+		netConn, err = net.Dial("tcp", hostPort(u, "80"))
+	case "wss":
+		netConn, err = tls.Dial("tcp", hostPort(u, "443"), &tls.Config{ServerName: u.Hostname()})
+	default:
+		return nil, nil, fmt.Errorf("websocket: unsupported scheme %q", u.Scheme)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keyBytes := make([]byte, 16)
+	if _, err := rand.Read(keyBytes); err != nil {
+		netConn.Close()
+		return nil, nil, err
+	}
+	key := base64.StdEncoding.EncodeToString(keyBytes)
+
+	req := &http.Request{
+		Method:     http.MethodGet,
+		URL:        u,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{},
+		Host:       u.Host,
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	req.Header.Set("Sec-WebSocket-Version", "13")
+
+	if err := req.Write(netConn); err != nil {
+		netConn.Close()
+		return nil, nil, err
+	}
+
+	br := bufio.NewReader(netConn)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		netConn.Close()
+		return nil, nil, err
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols ||
+		!headerContains(resp.Header, "Upgrade", "websocket") ||
+		resp.Header.Get("Sec-WebSocket-Accept") != acceptKey(key) {
+		netConn.Close()
+		return nil, resp, errors.New("websocket: bad handshake")
+	}
+
+	return newConn(netConn, br, false), resp, nil
+}
+
+func newConn(netConn net.Conn, br *bufio.Reader, isServer bool) *Conn {
+	return &Conn{
+		conn:      netConn,
+		br:        br,
+		isServer:  isServer,
+		readLimit: DefaultReadLimit,
+	}
+}
+
+// SetReadLimit sets the maximum size of a message read from the peer.
+func (c *Conn) SetReadLimit(limit int64) {
+	c.readLimit = limit
+}
+
+// NetConn returns the underlying network connection.
+func (c *Conn) NetConn() net.Conn {
+	return c.conn
+}
+
+// ReadMessage reads the next text or binary message. Pings are answered and
+// pongs are skipped. A close frame from the peer is acknowledged and reported
+// as a *CloseError.
+func (c *Conn) ReadMessage() (int, []byte, error) {
+	var (
+		messageType int
+		message     []byte
+	)
+
+	for {
+		fin, opcode, payload, err := c.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+
+		switch opcode {
+		case PingMessage:
+			if err := c.writeFrame(PongMessage, payload); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case PongMessage:
+			continue
+		case CloseMessage:
+			closeErr := &CloseError{Code: CloseNoStatusPresent}
+			if len(payload) >= 2 {
+				closeErr.Code = int(binary.BigEndian.Uint16(payload))
+				closeErr.Text = string(payload[2:])
+			}
+			c.CloseWithCode(closeErr.Code, "")
+			return 0, nil, closeErr
+		case TextMessage, BinaryMessage:
+			if messageType != 0 {
+				return 0, nil, c.protocolError("unexpected data frame during fragmented message")
+			}
+			messageType = opcode
+		case continuationFrame:
+			if messageType == 0 {
+				return 0, nil, c.protocolError("unexpected continuation frame")
+			}
+		default:
+			return 0, nil, c.protocolError(fmt.Sprintf("unknown opcode %d", opcode))
+		}
+
+		if int64(len(message)+len(payload)) > c.readLimit {
+			c.CloseWithCode(CloseMessageTooBig, "")
+			return 0, nil, ErrReadLimit
+		}
+		message = append(message, payload...)
+
+		if fin {
+			return messageType, message, nil
+		}
+	}
+}
+
+// WriteMessage writes a single unfragmented message.
+func (c *Conn) WriteMessage(messageType int, data []byte) error {
+	if messageType != TextMessage && messageType != BinaryMessage {
+		return fmt.Errorf("websocket: invalid message type %d", messageType)
+	}
+	return c.writeFrame(messageType, data)
+}
+
+// ReadJSON reads the next message and decodes it as JSON into v.
+func (c *Conn) ReadJSON(v interface{}) error {
+	_, data, err := c.ReadMessage()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// WriteJSON encodes v as JSON and sends it as a text message.
+func (c *Conn) WriteJSON(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.WriteMessage(TextMessage, data)
+}
+
+// Ping sends a ping control frame.
+func (c *Conn) Ping(data []byte) error {
+	return c.writeFrame(PingMessage, data)
+}
+
+// Close sends a normal closure frame and closes the connection. It is safe to
+// call more than once.
+func (c *Conn) Close() error {
+	return c.CloseWithCode(CloseNormalClosure, "")
+}
+
+// CloseWithCode sends a close frame with the given code and reason and
+// closes the connection.
+func (c *Conn) CloseWithCode(code int, reason string) error {
+	c.closeMu.Lock()
+	defer c.closeMu.Unlock()
+
+	if c.closed {
+		return nil
+	}
+	c.writeClose(code, reason)
+	c.closed = true
+	return c.conn.Close()
+}
+
+func (c *Conn) protocolError(msg string) error {
+	c.CloseWithCode(CloseProtocolError, "")
+	return errors.New("websocket: " + msg)
+}
+
+func (c *Conn) writeClose(code int, reason string) error {
+	if code == CloseNoStatusPresent {
+		return c.writeFrame(CloseMessage, nil)
+	}
+
+	payload := make([]byte, 2+len(reason))
+	binary.BigEndian.PutUint16(payload, uint16(code))
+	copy(payload[2:], reason)
+	return c.writeFrame(CloseMessage, payload)
+}
+
+func (c *Conn) readFrame() (bool, int, []byte, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(c.br, header[:]); err != nil {
+		return false, 0, nil, err
+	}
+
+	fin := header[0]&finalBit != 0
+	opcode := int(header[0] & 0x0f)
+	if header[0]&0x70 != 0 {
+		return false, 0, nil, c.protocolError("reserved bits set")
+	}
+
+	masked := header[1]&maskBit != 0
+	if masked != c.isServer {
+		return false, 0, nil, c.protocolError("bad frame masking")
+	}
+
+	length := int64(header[1] & 0x7f)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = int64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = int64(binary.BigEndian.Uint64(ext[:]))
+	}
+
+	if opcode >= CloseMessage && (length > 125 || !fin) {
+		return false, 0, nil, c.protocolError("invalid control frame")
+	}
+	if length > c.readLimit || length < 0 {
+		c.CloseWithCode(CloseMessageTooBig, "")
+		return false, 0, nil, ErrReadLimit
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(c.br, mask[:]); err != nil {
+			return false, 0, nil, err
+		}
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.br, payload); err != nil {
+		return false, 0, nil, err
+	}
+	if masked {
+		maskBytes(mask, payload)
+	}
+
+	return fin, opcode, payload, nil
+}
+
+func (c *Conn) writeFrame(opcode int, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	frame := make([]byte, 0, 14+len(payload))
+	frame = append(frame, finalBit|byte(opcode))
+
+	var maskFlag byte
+	if !c.isServer {
+		maskFlag = maskBit
+	}
+
+	switch {
+	case len(payload) <= 125:
+		frame = append(frame, maskFlag|byte(len(payload)))
+	case len(payload) <= 0xffff:
+		frame = append(frame, maskFlag|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(len(payload)))
+	default:
+		frame = append(frame, maskFlag|127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(len(payload)))
+	}
+
+	if c.isServer {
+		frame = append(frame, payload...)
+	} else {
+		var mask [4]byte
+		if _, err := rand.Read(mask[:]); err != nil {
+			return err
+		}
+		frame = append(frame, mask[:]...)
+		start := len(frame)
+		frame = append(frame, payload...)
+		maskBytes(mask, frame[start:])
+	}
+
+	_, err := c.conn.Write(frame)
+	return err
+}
+
+func maskBytes(mask [4]byte, b []byte) {
+	for i := range b {
+		b[i] ^= mask[i%4]
+	}
+}
+
+func acceptKey(key string) string {
+	h := sha1.New()
+	h.Write([]byte(key + magicKey))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+func headerContains(header http.Header, name, token string) bool {
+	for _, value := range header.Values(name) {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func hostPort(u *url.URL, defaultPort string) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+	return net.JoinHostPort(u.Hostname(), defaultPort)
+}
+
+func handshakeError(w http.ResponseWriter, status int, reason string) error {
+	http.Error(w, http.StatusText(status), status)
+	return errors.New("websocket: " + reason)
+}
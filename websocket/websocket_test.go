@@ -0,0 +1,114 @@
+package websocket_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sattvikc/go-simpleapi/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+func echoServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Upgrade(w, r)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		for {
+			messageType, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if err := conn.WriteMessage(messageType, data); err != nil {
+				return
+			}
+		}
+	}))
+}
+
+func TestEcho(t *testing.T) {
+	server := echoServer(t)
+	defer server.Close()
+
+	conn, _, err := websocket.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	{
+		assert.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte("hello")))
+		messageType, data, err := conn.ReadMessage()
+		assert.NoError(t, err)
+		assert.Equal(t, websocket.TextMessage, messageType)
+		assert.Equal(t, "hello", string(data))
+	}
+
+	{
+		large := strings.Repeat("x", 70000)
+		assert.NoError(t, conn.WriteMessage(websocket.BinaryMessage, []byte(large)))
+		messageType, data, err := conn.ReadMessage()
+		assert.NoError(t, err)
+		assert.Equal(t, websocket.BinaryMessage, messageType)
+		assert.Equal(t, large, string(data))
+	}
+
+	{
+		type message struct {
+			Text string `json:"text"`
+		}
+		assert.NoError(t, conn.Ping(nil))
+		assert.NoError(t, conn.WriteJSON(message{Text: "json"}))
+		var got message
+		assert.NoError(t, conn.ReadJSON(&got))
+		assert.Equal(t, "json", got.Text)
+	}
+}
+
+func TestReadLimit(t *testing.T) {
+	server := echoServer(t)
+	defer server.Close()
+
+	conn, _, err := websocket.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	conn.SetReadLimit(10)
+	assert.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte("more than ten bytes")))
+	_, _, err = conn.ReadMessage()
+	assert.ErrorIs(t, err, websocket.ErrReadLimit)
+}
+
+func TestOrigin(t *testing.T) {
+	server := echoServer(t)
+	defer server.Close()
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	conn, _, err := websocket.Dial(url, http.Header{"Origin": []string{server.URL}})
+	assert.NoError(t, err)
+	conn.Close()
+
+	_, resp, err := websocket.Dial(url, http.Header{"Origin": []string{"https://attacker.example"}})
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	allowed := websocket.AllowOrigins("https://app.example")
+	r := httptest.NewRequest(http.MethodGet, "http://api.example/", nil)
+	r.Header.Set("Origin", "https://app.example")
+	assert.True(t, allowed(r))
+	r.Header.Set("Origin", "https://attacker.example")
+	assert.False(t, allowed(r))
+	assert.True(t, websocket.AllowOrigins("*")(r))
+}
+
+func TestRejectsPlainRequest(t *testing.T) {
+	server := echoServer(t)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}